@@ -0,0 +1,304 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// adminShutdownTimeout limits the time to finish pending admin requests on Stop()
+const adminShutdownTimeout = 5 * time.Second
+
+// admin is an optional component, which exposes the internals of the session over http.
+type admin struct {
+	// Host is the interface the admin server listens on. It defaults to localhost, so the internals aren't exposed.
+	Host string `boot:"config,key:BOOT_ADMIN_HOST,default:localhost"`
+	// Port is the port the admin server listens on.
+	Port    int    `boot:"config,key:BOOT_ADMIN_PORT,default:8081"`
+	Health  Health `boot:"wire"`
+	session *Session
+	mutex   sync.Mutex
+	server  *http.Server
+	addr    net.Addr
+}
+
+var _ Process = (*admin)(nil) // Verify conformity to Process
+
+// adminGraph is the dependency graph of all components.
+type adminGraph struct {
+	Nodes []string    `json:"nodes"`
+	Edges []adminEdge `json:"edges"`
+}
+
+// adminEdge points from a component to a wired dependency.
+type adminEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// adminComponent is the JSON representation of a ComponentInfo.
+type adminComponent struct {
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`
+	State        string     `json:"state"`
+	Dependencies []string   `json:"dependencies,omitempty"`
+	InitDuration string     `json:"initDuration"`
+	StartTime    *time.Time `json:"startTime,omitempty"`
+	Err          string     `json:"error,omitempty"`
+}
+
+// adminRuntime contains the Go runtime statistics.
+type adminRuntime struct {
+	Info         RuntimeInfo    `json:"info"`
+	Limits       ResourceLimits `json:"limits"`
+	Version      string         `json:"version"`
+	Phase        string         `json:"phase"`
+	NumCPU       int            `json:"numCPU"`
+	GoMaxProcs   int            `json:"gomaxprocs"`
+	Goroutines   int            `json:"goroutines"`
+	HeapAlloc    uint64         `json:"heapAlloc"`
+	HeapSys      uint64         `json:"heapSys"`
+	HeapObjects  uint64         `json:"heapObjects"`
+	TotalAlloc   uint64         `json:"totalAlloc"`
+	Sys          uint64         `json:"sys"`
+	NumGC        uint32         `json:"numGC"`
+	PauseTotalNs uint64         `json:"pauseTotalNs"`
+}
+
+// newAdminComponents converts the component infos into their JSON representation
+func newAdminComponents(infos []ComponentInfo) []adminComponent {
+	components := make([]adminComponent, 0, len(infos))
+	for _, info := range infos {
+		cmp := adminComponent{
+			Name:         info.Name,
+			Type:         info.Type,
+			State:        info.State.String(),
+			Dependencies: info.Dependencies,
+			InitDuration: info.InitDuration.String(),
+		}
+		if !info.StartTime.IsZero() {
+			startTime := info.StartTime
+			cmp.StartTime = &startTime
+		}
+		if info.Err != nil {
+			cmp.Err = info.Err.Error()
+		}
+		components = append(components, cmp)
+	}
+	return components
+}
+
+func newAdmin(s *Session) *admin {
+	return &admin{
+		session: s,
+	}
+}
+
+// Init is described in the Component interface
+func (a *admin) Init() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.server = &http.Server{
+		Addr:              net.JoinHostPort(a.Host, strconv.Itoa(a.Port)),
+		Handler:           a.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return nil
+}
+
+// Start is described in the Process interface
+func (a *admin) Start() error {
+	a.mutex.Lock()
+	server := a.server
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		a.mutex.Unlock()
+		return err
+	}
+	a.addr = listener.Addr()
+	a.mutex.Unlock()
+	Logger.Info.Printf("admin endpoint listening on http://%s/admin/", listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop is described in the Process interface
+func (a *admin) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), adminShutdownTimeout)
+	defer cancel()
+	a.mutex.Lock()
+	server := a.server
+	a.mutex.Unlock()
+	return server.Shutdown(ctx)
+}
+
+// address returns the address the server listens on. It is nil until the server was started.
+func (a *admin) address() net.Addr {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.addr
+}
+
+// handler returns the routes of the admin endpoint
+func (a *admin) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/components", a.handleComponents)
+	mux.HandleFunc("/admin/graph", a.handleGraph)
+	mux.HandleFunc("/admin/config", a.handleConfig)
+	mux.HandleFunc("/admin/modules", a.handleModules)
+	mux.HandleFunc("/admin/health", a.handleReadiness)
+	mux.HandleFunc("/admin/health/live", a.handleLiveness)
+	mux.HandleFunc("/admin/health/ready", a.handleReadiness)
+	mux.HandleFunc("/admin/subscriptions", a.handleSubscriptions)
+	mux.HandleFunc("/admin/flags", a.handleFlags)
+	mux.HandleFunc("/admin/runtime", a.handleRuntime)
+	mux.HandleFunc("/admin/timeline", a.handleTimeline)
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+func (a *admin) handleComponents(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, newAdminComponents(a.session.Components()))
+}
+
+func (a *admin) handleGraph(w http.ResponseWriter, r *http.Request) {
+	graph := adminGraph{Nodes: []string{}, Edges: []adminEdge{}}
+	for _, info := range a.session.Components() {
+		graph.Nodes = append(graph.Nodes, info.FullName())
+		for _, dependency := range info.Dependencies {
+			graph.Edges = append(graph.Edges, adminEdge{From: info.FullName(), To: dependency})
+		}
+	}
+	if r.URL.Query().Get("format") != "dot" {
+		writeJSON(w, http.StatusOK, graph)
+		return
+	}
+	w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	var b strings.Builder
+	b.WriteString("digraph boot {\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "\t%q;\n", node)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&b, "\t%q -> %q;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func (a *admin) handleConfig(w http.ResponseWriter, _ *http.Request) {
+	values := a.session.Configuration()
+	if values == nil {
+		values = []ConfigValue{}
+	}
+	writeJSON(w, http.StatusOK, values)
+}
+
+func (a *admin) handleModules(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, a.session.Modules())
+}
+
+func (a *admin) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, a.Health.Liveness(r.Context()))
+}
+
+func (a *admin) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, a.Health.Readiness(r.Context()))
+}
+
+func (a *admin) handleSubscriptions(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, a.session.eventbus.subscriptions())
+}
+
+func (a *admin) handleFlags(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, a.session.runtime.Flags())
+}
+
+func (a *admin) handleRuntime(w http.ResponseWriter, _ *http.Request) {
+	var mem goruntime.MemStats
+	goruntime.ReadMemStats(&mem)
+	writeJSON(w, http.StatusOK, adminRuntime{
+		Info:         a.session.runtime.Info(),
+		Limits:       a.session.runtime.ResourceLimits(),
+		Version:      goruntime.Version(),
+		Phase:        a.session.currentPhase().String(),
+		NumCPU:       goruntime.NumCPU(),
+		GoMaxProcs:   goruntime.GOMAXPROCS(0),
+		Goroutines:   goruntime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapSys:      mem.HeapSys,
+		HeapObjects:  mem.HeapObjects,
+		TotalAlloc:   mem.TotalAlloc,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		PauseTotalNs: mem.PauseTotalNs,
+	})
+}
+
+func (a *admin) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("format") == "trace" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = a.session.Timeline().WriteChromeTrace(w)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_ = a.session.Timeline().WriteTable(w)
+}
+
+// writeHealth responds with the health report and 503, if the status is down
+func writeHealth(w http.ResponseWriter, report HealthReport) {
+	status := http.StatusOK
+	if report.Status == HealthDown {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// writeJSON responds with the value encoded as JSON
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		Logger.Error.Printf("admin response failed: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type adminConfigTest struct {
+	User     string   `boot:"config,key:ADMIN_TEST_USER,default:madjax"`
+	Password string   `boot:"config,key:ADMIN_TEST_PASSWORD,default:geheim"`
+	Dsn      string   `boot:"config,key:ADMIN_TEST_DSN,default:'postgres://secret@localhost',secret"`
+	Eventbus EventBus `boot:"wire"`
+}
+
+func (c *adminConfigTest) Init() error {
+	return c.Eventbus.Subscribe(func(event ReloadEvent) {})
+}
+
+func startAdminTest(t *testing.T) (*admin, *Handle) {
+	t.Helper()
+	t.Setenv("BOOT_ADMIN_PORT", "0")
+	a := newAdmin(nil)
+	s := newTestSessionWithOptions(Options{}, a, &adminConfigTest{})
+	a.session = s.Session
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := h.Stop(context.Background()); err != nil {
+			t.Errorf("Stop() error = %v", err)
+		}
+	})
+	for i := 0; a.address() == nil; i++ {
+		if i > 100 {
+			t.Fatal("admin endpoint not started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return a, h
+}
+
+func TestAdminEndpoints(t *testing.T) {
+	a, _ := startAdminTest(t)
+	tests := []struct {
+		path   string
+		status int
+		want   []string
+		forbid []string
+	}{
+		{path: "/admin/components", status: http.StatusOK, want: []string{`"state": "started"`, "adminConfigTest", `"initialized"`}},
+		{path: "/admin/graph", status: http.StatusOK, want: []string{`"from": "default:github.com/boot-go/boot/adminConfigTest"`}},
+		{path: "/admin/graph?format=dot", status: http.StatusOK, want: []string{"digraph boot", "->"}},
+		{path: "/admin/config", status: http.StatusOK, want: []string{`"value": "madjax"`, "ADMIN_TEST_PASSWORD", redacted}, forbid: []string{"geheim", "secret@localhost"}},
+		{path: "/admin/health", status: http.StatusOK, want: []string{`"status": "up"`}},
+		{path: "/admin/health/live", status: http.StatusOK, want: []string{`"status": "up"`}},
+		{path: "/admin/subscriptions", status: http.StatusOK, want: []string{"github.com/boot-go/boot/ReloadEvent"}},
+		{path: "/admin/flags", status: http.StatusOK, want: []string{"[]"}},
+		{path: "/admin/runtime", status: http.StatusOK, want: []string{`"phase": "running"`, "goroutines"}},
+		{path: "/admin/timeline", status: http.StatusOK, want: []string{"DURATION"}},
+		{path: "/admin/timeline?format=trace", status: http.StatusOK, want: []string{"traceEvents"}},
+		{path: "/debug/pprof/", status: http.StatusOK, want: []string{"goroutine"}},
+		{path: "/unknown", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get("http://" + a.address().String() + tt.path)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("body doesn't contain %q:\n%s", want, body)
+				}
+			}
+			for _, forbid := range tt.forbid {
+				if strings.Contains(string(body), forbid) {
+					t.Errorf("body contains %q:\n%s", forbid, body)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminConfigurationRedacted(t *testing.T) {
+	a, _ := startAdminTest(t)
+	got := map[string]ConfigValue{}
+	for _, value := range a.session.Configuration() {
+		got[value.Key] = value
+	}
+	tests := []struct {
+		key    string
+		value  string
+		secret bool
+	}{
+		{key: "ADMIN_TEST_USER", value: "madjax"},
+		{key: "ADMIN_TEST_PASSWORD", value: redacted, secret: true},
+		{key: "ADMIN_TEST_DSN", value: redacted, secret: true},
+		{key: "BOOT_ADMIN_PORT", value: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			value, ok := got[tt.key]
+			if !ok {
+				t.Fatalf("Configuration() doesn't contain %s", tt.key)
+			}
+			if value.Value != tt.value || value.Secret != tt.secret {
+				t.Errorf("Configuration() = %+v, want value %s and secret %v", value, tt.value, tt.secret)
+			}
+		})
+	}
+	if !got["ADMIN_TEST_USER"].Default || got["BOOT_ADMIN_PORT"].Default {
+		t.Errorf("Configuration() default flags are wrong: %+v", got)
+	}
+}
+
+func TestAdminReadinessDown(t *testing.T) {
+	a := newAdmin(newTestSession().Session)
+	a.Health = newHealth(a.session)
+	rec := &statusRecorder{header: http.Header{}}
+	a.handler().ServeHTTP(rec, mustRequest(t, "/admin/health/ready"))
+	if rec.status != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.status, http.StatusServiceUnavailable)
+	}
+	var report struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(rec.body.String()), &report); err != nil || report.Status != "down" {
+		t.Errorf("body = %s, want status down", rec.body.String())
+	}
+}
+
+type statusRecorder struct {
+	header http.Header
+	status int
+	body   strings.Builder
+}
+
+func (r *statusRecorder) Header() http.Header         { return r.header }
+func (r *statusRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *statusRecorder) WriteHeader(status int)      { r.status = status }
+
+func mustRequest(t *testing.T, path string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
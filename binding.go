@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Binding declares the dependencies and configuration values of a component without struct tags, e.g. for
+// types of other packages. It is created with FactoryContext.Bind and feeds the same wiring and configuration
+// as the boot tags, e.g.
+//
+//	ctx.Bind(c).Wire(&c.Store).Name("primary").Config(&c.Port, "PORT").Default("8080")
+type Binding struct {
+	component Component
+	fields    []*FieldBinding
+}
+
+// FieldBinding declares, how one field is wired or configured. It provides the methods of the Binding to
+// bind further fields.
+type FieldBinding struct {
+	*Binding
+	field any
+	tag   *tag
+}
+
+// newBinding creates an empty binding for the component.
+func newBinding(component Component) *Binding {
+	return &Binding{component: component}
+}
+
+// Wire binds the field to the component registered with the default name. The field must be passed as pointer,
+// e.g. &c.Store.
+func (b *Binding) Wire(field any) *FieldBinding {
+	return b.bind(field, fieldTagWire)
+}
+
+// Config binds the field to the configuration key. The field must be passed as pointer, e.g. &c.Port.
+func (b *Binding) Config(field any, key string) *FieldBinding {
+	return b.bind(field, fieldTagConfig).option(fieldTagWireKey, key)
+}
+
+// Name sets the registration name of the wired component.
+func (f *FieldBinding) Name(name string) *FieldBinding {
+	return f.option(fieldTagName, name)
+}
+
+// Default sets the default value, which is used when the configuration key isn't set.
+func (f *FieldBinding) Default(value string) *FieldBinding {
+	return f.option(fieldTagWireDefault, value)
+}
+
+// Template sets the namespaced configuration key of named components, e.g. {NAME}_DB_{KEY}.
+func (f *FieldBinding) Template(template string) *FieldBinding {
+	return f.option(fieldTagConfigTemplate, template)
+}
+
+// Secret marks the configuration value as secret, so it is never shown.
+func (f *FieldBinding) Secret() *FieldBinding {
+	return f.option(fieldTagConfigSecret, "")
+}
+
+// Panic stops the boot, if the configuration value is missing or invalid.
+func (f *FieldBinding) Panic() *FieldBinding {
+	return f.option(fieldTagWirePanic, "")
+}
+
+// bind adds a field binding of the kind
+func (b *Binding) bind(field any, kind string) *FieldBinding {
+	f := &FieldBinding{
+		Binding: b,
+		field:   field,
+		tag:     &tag{name: kind, options: make(map[string]string)},
+	}
+	b.fields = append(b.fields, f)
+	return f
+}
+
+// option sets an option like in the boot tag
+func (f *FieldBinding) option(name string, value string) *FieldBinding {
+	f.tag.options[name] = value
+	return f
+}
+
+// injections returns the bound fields of the component. The binding may be nil.
+func (b *Binding) injections(reflectedComponent reflect.Value) ([]injection, error) {
+	if b == nil {
+		return nil, nil
+	}
+	injections := make([]injection, 0, len(b.fields))
+	for _, f := range b.fields {
+		pointer := reflect.ValueOf(f.field)
+		if pointer.Kind() != reflect.Ptr || pointer.IsNil() {
+			return nil, &DependencyInjectionError{
+				error:  errors.New("bound field is not a pointer"),
+				detail: "<" + reflectedComponent.Type().Name() + "." + QualifiedName(f.field) + ">",
+			}
+		}
+		field, ok := boundField(reflectedComponent, pointer)
+		if !ok {
+			return nil, &DependencyInjectionError{
+				error:  errors.New("bound pointer doesn't address a field of"),
+				detail: "<" + reflectedComponent.Type().Name() + "." + pointer.Elem().Type().String() + ">",
+			}
+		}
+		injections = append(injections, injection{
+			field:      field,
+			fieldValue: pointer.Elem(),
+			tag:        f.tag,
+		})
+	}
+	return injections, nil
+}
+
+// boundField returns the struct field of the component, which is addressed by the pointer. It returns false,
+// if the pointer addresses a value outside the component.
+func boundField(reflectedComponent reflect.Value, pointer reflect.Value) (reflect.StructField, bool) {
+	if reflectedComponent.Kind() == reflect.Struct && reflectedComponent.CanAddr() {
+		for j := 0; j < reflectedComponent.NumField(); j++ {
+			fieldValue := reflectedComponent.Field(j)
+			if fieldValue.Addr().Pointer() == pointer.Pointer() && fieldValue.Type() == pointer.Elem().Type() {
+				field := reflectedComponent.Type().Field(j)
+				field.Tag = ""
+				return field, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
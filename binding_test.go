@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type bindingStoreTest struct{}
+
+func (c *bindingStoreTest) Init() error { return nil }
+
+type bindingTest struct {
+	store Ref[*bindingStoreTest]
+	Port  int
+	Token string
+}
+
+func (c *bindingTest) Init() error { return nil }
+
+func TestBinding(t *testing.T) {
+	t.Setenv("BINDING_TOKEN", "token")
+	s := newTestSession(&bootProcessesComponent{})
+	cmp := &bindingTest{}
+	_ = s.RegisterName("primary", func() Component { return &bindingStoreTest{} })
+	err := s.RegisterFactory(func(ctx FactoryContext) (Component, error) {
+		ctx.Bind(cmp).
+			Wire(&cmp.store).Name("primary").
+			Config(&cmp.Port, "BINDING_PORT").Default("8080").
+			Config(&cmp.Token, "BINDING_TOKEN").Secret()
+		return cmp, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterFactory() error = %v", err)
+	}
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = h.Stop(context.Background()) }()
+	if cmp.store.Get() == nil || cmp.Port != 8080 || cmp.Token != "token" {
+		t.Errorf("unexpected component %+v", cmp)
+	}
+	for _, value := range s.Configuration() {
+		if value.Key == "BINDING_TOKEN" && (value.Field != "Token" || value.Value != redacted) {
+			t.Errorf("unexpected configuration value %+v", value)
+		}
+	}
+	store := &bindingStoreTest{}
+	if err := s.ReplaceComponent("primary:github.com/boot-go/boot/bindingStoreTest", func() Component { return store }); err != nil {
+		t.Fatalf("ReplaceComponent() error = %v", err)
+	}
+	if cmp.store.Get() != store {
+		t.Errorf("bound reference must refer to the replacement")
+	}
+}
+
+func TestBindingErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		bind func(ctx FactoryContext, c *bindingTest)
+		want error
+	}{
+		{name: "not a pointer", bind: func(ctx FactoryContext, c *bindingTest) { ctx.Bind(c).Wire(c.Port) }, want: ErrInjection},
+		{name: "outside the component", bind: func(ctx FactoryContext, c *bindingTest) {
+			port := 0
+			ctx.Bind(c).Config(&port, "BINDING_PORT").Default("8080")
+		}, want: ErrInjection},
+		{name: "not found", bind: func(ctx FactoryContext, c *bindingTest) { ctx.Bind(c).Wire(&c.store).Name("missing") }, want: ErrInjection},
+		{name: "invalid value", bind: func(ctx FactoryContext, c *bindingTest) {
+			ctx.Bind(c).Config(&c.Port, "BINDING_PORT").Default("port").Panic()
+		}, want: ErrConfiguration},
+		{name: "missing value", bind: func(ctx FactoryContext, c *bindingTest) { ctx.Bind(c).Config(&c.Port, "BINDING_PORT").Panic() }, want: ErrConfiguration},
+		{name: "other component", bind: func(ctx FactoryContext, c *bindingTest) { ctx.Bind(&bindingTest{}).Config(&c.Port, "BINDING_PORT") }, want: errFactoryBindingMismatch},
+		{name: "rebound component", bind: func(ctx FactoryContext, c *bindingTest) {
+			other := &bindingTest{}
+			ctx.Bind(other).Config(&other.Port, "BINDING_PORT")
+			ctx.Bind(c).Config(&c.Port, "BINDING_PORT")
+		}, want: errFactoryBindingMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession()
+			_ = s.RegisterFactory(func(ctx FactoryContext) (Component, error) {
+				c := &bindingTest{}
+				tt.bind(ctx, c)
+				return c, nil
+			})
+			if _, err := s.Start(context.Background()); !errors.Is(err, tt.want) {
+				t.Errorf("Start() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
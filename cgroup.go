@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	gort "runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	kilobyte = 1024
+	megabyte = kilobyte * kilobyte
+)
+
+// cgroupUnlimitedMemory is the lowest value, which is considered as unlimited memory by cgroup v1
+const cgroupUnlimitedMemory = int64(1) << 62
+
+var (
+	// tuning guards the tuning, because the Go runtime is tuned only once per process
+	tuning sync.Once
+	// tunedMemoryLimit is the soft memory limit of the garbage collector set by the tuning
+	tunedMemoryLimit int64
+)
+
+// ResourceLimits describes the CPU and memory limits of the container and how the Go runtime was tuned.
+type ResourceLimits struct {
+	// CgroupVersion is 1 or 2, or 0 if no cgroup was found
+	CgroupVersion int
+	// CPU is the number of CPUs available to the container. It is zero, if the CPU is unlimited.
+	CPU float64
+	// Memory is the memory limit of the container in bytes. It is zero, if the memory is unlimited.
+	Memory int64
+	// GoMaxProcs is the value of GOMAXPROCS
+	GoMaxProcs int
+	// MemoryLimit is the soft memory limit of the garbage collector in bytes. It is zero, if it wasn't set.
+	MemoryLimit int64
+}
+
+// readResourceLimits reads the cgroup v2 or v1 limits from the cgroup file system mounted at root
+func readResourceLimits(root string) ResourceLimits {
+	limits := ResourceLimits{GoMaxProcs: gort.GOMAXPROCS(0)}
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		limits.CgroupVersion = 2
+		if fields := readCgroupFields(root, "cpu.max"); len(fields) == 2 && fields[0] != "max" {
+			limits.CPU = cpuQuota(fields[0], fields[1])
+		}
+		if fields := readCgroupFields(root, "memory.max"); len(fields) == 1 && fields[0] != "max" {
+			limits.Memory, _ = strconv.ParseInt(fields[0], 10, 64)
+		}
+		return limits
+	}
+	quota := readCgroupFields(root, "cpu", "cpu.cfs_quota_us")
+	period := readCgroupFields(root, "cpu", "cpu.cfs_period_us")
+	memory := readCgroupFields(root, "memory", "memory.limit_in_bytes")
+	if quota == nil && memory == nil {
+		return limits
+	}
+	limits.CgroupVersion = 1
+	if len(quota) == 1 && len(period) == 1 {
+		limits.CPU = cpuQuota(quota[0], period[0])
+	}
+	if len(memory) == 1 {
+		if value, err := strconv.ParseInt(memory[0], 10, 64); err == nil && value < cgroupUnlimitedMemory {
+			limits.Memory = value
+		}
+	}
+	return limits
+}
+
+// readCgroupFields returns the space separated fields of a cgroup file or nil, if it can't be read
+func readCgroupFields(path ...string) []string {
+	content, err := os.ReadFile(filepath.Join(path...))
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(content))
+}
+
+// cpuQuota returns the number of CPUs or zero, if the quota is unlimited or invalid
+func cpuQuota(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// tune sets GOMAXPROCS and the soft memory limit of the garbage collector to match the limits. The
+// environment variables GOMAXPROCS and GOMEMLIMIT have precedence. The memory limit is set to the
+// given percentage of the container memory, so there is headroom for memory not managed by Go.
+func (l ResourceLimits) tune(memoryPercent int) ResourceLimits {
+	if _, ok := os.LookupEnv("GOMAXPROCS"); !ok && l.CPU > 0 {
+		procs := int(math.Ceil(l.CPU))
+		if procs < gort.NumCPU() {
+			gort.GOMAXPROCS(procs)
+		}
+	}
+	l.GoMaxProcs = gort.GOMAXPROCS(0)
+	if _, ok := os.LookupEnv("GOMEMLIMIT"); !ok && l.Memory > 0 && memoryPercent > 0 {
+		l.MemoryLimit = setMemoryLimit(l.Memory / 100 * int64(memoryPercent))
+	}
+	return l
+}
+
+// tuneOnce tunes the Go runtime to the limits, if it wasn't tuned by another session of the process before,
+// and logs the tuned values. It returns the limits with the current tuning.
+func (l ResourceLimits) tuneOnce(memoryPercent int) ResourceLimits {
+	tuning.Do(func() {
+		tuned := l.tune(memoryPercent)
+		tunedMemoryLimit = tuned.MemoryLimit
+		Logger.Info.Printf("tuned runtime /// GOMAXPROCS/%d GOMEMLIMIT/%s\n", tuned.GoMaxProcs,
+			formatLimit(tuned.MemoryLimit > 0, strconv.FormatInt(tuned.MemoryLimit/megabyte, 10)+"MB"))
+	})
+	l.GoMaxProcs = gort.GOMAXPROCS(0)
+	l.MemoryLimit = tunedMemoryLimit
+	return l
+}
+
+// formatLimit returns the value or none, if there is no limit
+func formatLimit(limited bool, value string) string {
+	if !limited {
+		return "none"
+	}
+	return value
+}
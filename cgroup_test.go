@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	gort "runtime"
+	"sync"
+	"testing"
+)
+
+func writeCgroupFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestReadResourceLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		version int
+		cpu     float64
+		memory  int64
+	}{
+		{name: "no cgroup"},
+		{
+			name:    "v2 limited",
+			files:   map[string]string{"cgroup.controllers": "cpu memory", "cpu.max": "150000 100000\n", "memory.max": "536870912\n"},
+			version: 2, cpu: 1.5, memory: 512 * megabyte,
+		},
+		{
+			name:    "v2 unlimited",
+			files:   map[string]string{"cgroup.controllers": "cpu memory", "cpu.max": "max 100000\n", "memory.max": "max\n"},
+			version: 2,
+		},
+		{
+			name: "v1 limited",
+			files: map[string]string{"cpu/cpu.cfs_quota_us": "200000\n", "cpu/cpu.cfs_period_us": "100000\n",
+				"memory/memory.limit_in_bytes": "1073741824\n"},
+			version: 1, cpu: 2, memory: 1024 * megabyte,
+		},
+		{
+			name: "v1 unlimited",
+			files: map[string]string{"cpu/cpu.cfs_quota_us": "-1\n", "cpu/cpu.cfs_period_us": "100000\n",
+				"memory/memory.limit_in_bytes": "9223372036854771712\n"},
+			version: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := readResourceLimits(writeCgroupFiles(t, tt.files))
+			if got.CgroupVersion != tt.version || got.CPU != tt.cpu || got.Memory != tt.memory {
+				t.Errorf("readResourceLimits() = %+v, want version %d, cpu %v and memory %d", got, tt.version, tt.cpu, tt.memory)
+			}
+		})
+	}
+}
+
+func TestResourceLimitsTune(t *testing.T) {
+	defer gort.GOMAXPROCS(gort.GOMAXPROCS(0))
+	tests := []struct {
+		name       string
+		limits     ResourceLimits
+		percent    int
+		gomaxprocs int
+		memory     int64
+	}{
+		{name: "unlimited", gomaxprocs: gort.GOMAXPROCS(0)},
+		{name: "one cpu", limits: ResourceLimits{CPU: 0.5}, gomaxprocs: 1},
+		{name: "memory", limits: ResourceLimits{Memory: 100 * megabyte}, percent: 90, gomaxprocs: gort.GOMAXPROCS(0), memory: 90 * megabyte},
+		{name: "memory without percent", limits: ResourceLimits{Memory: 100 * megabyte}, gomaxprocs: gort.GOMAXPROCS(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.limits.tune(tt.percent)
+			if got.GoMaxProcs != tt.gomaxprocs {
+				t.Errorf("tune() GOMAXPROCS = %d, want %d", got.GoMaxProcs, tt.gomaxprocs)
+			}
+			if memoryLimitSupported && got.MemoryLimit != tt.memory {
+				t.Errorf("tune() memory limit = %d, want %d", got.MemoryLimit, tt.memory)
+			}
+		})
+	}
+	setMemoryLimit(math.MaxInt64)
+}
+
+func TestRuntimeResourceLimits(t *testing.T) {
+	t.Setenv("BOOT_CGROUP_ROOT", writeCgroupFiles(t, map[string]string{"cgroup.controllers": "", "cpu.max": "100000 100000"}))
+	procs := gort.GOMAXPROCS(0)
+	s := newTestSession()
+	if err := s.Go(); err != nil {
+		t.Fatalf("Go() error = %v", err)
+	}
+	limits := s.runtime.ResourceLimits()
+	if limits.CgroupVersion != 2 || limits.CPU != 1 {
+		t.Errorf("ResourceLimits() = %+v, want cgroup v2 with 1 cpu", limits)
+	}
+	if limits.GoMaxProcs != procs {
+		t.Errorf("GOMAXPROCS = %d, want %d, because the tuning is disabled by default", limits.GoMaxProcs, procs)
+	}
+}
+
+func TestResourceLimitsTuneOnce(t *testing.T) {
+	defer gort.GOMAXPROCS(gort.GOMAXPROCS(0))
+	defer func() { tuning = sync.Once{} }()
+	tuning = sync.Once{}
+	if got := (ResourceLimits{CPU: 0.5}).tuneOnce(0); got.GoMaxProcs != 1 {
+		t.Errorf("tuneOnce() GOMAXPROCS = %d, want 1", got.GoMaxProcs)
+	}
+	if got := (ResourceLimits{CPU: 2}).tuneOnce(0); got.GoMaxProcs != 1 {
+		t.Errorf("tuneOnce() GOMAXPROCS = %d, want 1, because the runtime is tuned only once", got.GoMaxProcs)
+	}
+}
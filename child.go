@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ChildOptions contains the options for a child session.
+type ChildOptions struct {
+	// Mode is a list of flags, which is used in addition to the flags of the parent session by the conditions
+	// and factories of the child session. The Runtime component is shared with the parent session.
+	Mode []Flag
+	// ShutdownTimeout limits the time to stop all components of the child session.
+	ShutdownTimeout time.Duration
+	// BubbleEvents publishes the events of the child session on the event bus of the parent session, too.
+	// The lifecycle events of the child session aren't published on the parent event bus.
+	BubbleEvents bool
+}
+
+// children contains the handles of all running child sessions.
+type children struct {
+	mutex   sync.Mutex
+	handles []*Handle
+}
+
+var errChildSessionParentNotRunning = errors.New("child session requires a running parent session")
+
+// NewChild creates a child session, e.g. for a tenant or a plugin. The child session has its own components,
+// lifecycle and event bus. Dependencies, which aren't provided by the child session, are wired from the
+// parent session, including the Runtime. The child session can only be started while the parent session is
+// running. It runs until its Handle is stopped or it is stopped before the parent session stops its components.
+func (s *Session) NewChild(options ChildOptions) *Session {
+	child := newSession(Options{
+		Mode:            append(append([]Flag{}, s.option.Mode...), options.Mode...),
+		ShutdownTimeout: options.ShutdownTimeout,
+	}, s)
+	if options.BubbleEvents {
+		child.eventbus.parent = s.eventbus
+	}
+	return child
+}
+
+// parentRegistry returns the registry of the parent session or nil, if the session has no parent.
+func (s *Session) parentRegistry() (*registry, error) {
+	if s.parent == nil {
+		return nil, nil
+	}
+	s.parent.changeMutex.Lock()
+	defer s.parent.changeMutex.Unlock()
+	if s.parent.phase != running {
+		return nil, errChildSessionParentNotRunning
+	}
+	return s.parent.registry, nil
+}
+
+// addChild adds the handle of a started child session. It fails, if the session isn't running anymore.
+func (s *Session) addChild(h *Handle) error {
+	s.changeMutex.Lock()
+	defer s.changeMutex.Unlock()
+	if s.phase != running {
+		return errChildSessionParentNotRunning
+	}
+	s.children.mutex.Lock()
+	defer s.children.mutex.Unlock()
+	s.children.handles = append(s.children.handles, h)
+	go func() {
+		<-h.Done()
+		s.removeChild(h)
+	}()
+	return nil
+}
+
+// removeChild removes the handle of a stopped child session.
+func (s *Session) removeChild(h *Handle) {
+	s.children.mutex.Lock()
+	defer s.children.mutex.Unlock()
+	for i, handle := range s.children.handles {
+		if handle == h {
+			s.children.handles = append(s.children.handles[:i], s.children.handles[i+1:]...)
+			return
+		}
+	}
+}
+
+// stopChildren stops all running child sessions in reverse order and waits until they are stopped.
+func (s *Session) stopChildren() {
+	s.children.mutex.Lock()
+	handles := append([]*Handle(nil), s.children.handles...)
+	s.children.mutex.Unlock()
+	for i := range handles {
+		h := handles[len(handles)-i-1]
+		if err := h.Stop(context.Background()); err != nil {
+			Logger.Error.Printf("stopping child session failed: %v", err)
+		}
+	}
+}
+
+// isSessionEvent returns true, if the event describes the lifecycle of a session.
+func isSessionEvent(event Event) bool {
+	switch event.(type) {
+	case BootingEvent, RunningEvent, StoppingEvent, ExitingEvent, ComponentEvent, ShutdownEvent, ReloadEvent, DiagnosticsEvent:
+		return true
+	}
+	return false
+}
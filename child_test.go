@@ -0,0 +1,207 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type childSharedTest struct{}
+
+func (c *childSharedTest) Init() error { return nil }
+
+type childConsumerTest struct {
+	Shared   *childSharedTest `boot:"wire"`
+	Eventbus EventBus         `boot:"wire"`
+	Runtime  Runtime          `boot:"wire"`
+}
+
+func (c *childConsumerTest) Init() error { return nil }
+
+type childEventTest struct{}
+
+// childStopTest records the order in which the processes were stopped
+type childStopTest struct {
+	name  string
+	order *[]string
+	mutex *sync.Mutex
+	block chan struct{}
+}
+
+func (c *childStopTest) Init() error {
+	c.block = make(chan struct{})
+	return nil
+}
+
+func (c *childStopTest) Start() error {
+	<-c.block
+	return nil
+}
+
+func (c *childStopTest) Stop() error {
+	c.mutex.Lock()
+	*c.order = append(*c.order, c.name)
+	c.mutex.Unlock()
+	close(c.block)
+	return nil
+}
+
+func startChildTest(t *testing.T, parent *testSession, options ChildOptions, components ...Component) (*Session, *Handle) {
+	t.Helper()
+	child := parent.NewChild(options)
+	for _, component := range components {
+		component := component
+		if err := child.Register(func() Component { return component }); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	h, err := child.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return child, h
+}
+
+func TestChildSessionWiring(t *testing.T) {
+	shared := &childSharedTest{}
+	parent := newTestSession(shared, &bootProcessesComponent{})
+	ph, err := parent.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = ph.Stop(context.Background()) }()
+	consumer := &childConsumerTest{}
+	child, ch := startChildTest(t, parent, ChildOptions{}, consumer)
+	defer func() { _ = ch.Stop(context.Background()) }()
+	if consumer.Shared != shared {
+		t.Errorf("Shared = %p, want component of parent %p", consumer.Shared, shared)
+	}
+	if consumer.Eventbus != child.eventbus || consumer.Eventbus == parent.eventbus {
+		t.Errorf("Eventbus must be wired from the child session")
+	}
+	if !child.runtime.HasFlag(UnitTestFlag) {
+		t.Errorf("child session must inherit the flags of the parent session")
+	}
+	if consumer.Runtime != parent.runtime {
+		t.Errorf("Runtime must be wired from the parent session")
+	}
+	for _, info := range child.Components() {
+		if info.Type == QualifiedName(parent.runtime) {
+			t.Errorf("child session must not initialize its own runtime")
+		}
+	}
+}
+
+func TestChildSessionWithoutProcesses(t *testing.T) {
+	parent := newTestSession(&bootProcessesComponent{})
+	ph, err := parent.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	_, ch := startChildTest(t, parent, ChildOptions{}, &childSharedTest{})
+	select {
+	case <-ch.Done():
+		t.Fatal("child session without processes must run until it is stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := ph.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	select {
+	case <-ch.Done():
+	default:
+		t.Fatal("child session must be stopped with the parent session")
+	}
+}
+
+func TestChildSessionParentNotRunning(t *testing.T) {
+	parent := newTestSession()
+	child := parent.NewChild(ChildOptions{})
+	if _, err := child.Start(context.Background()); !errors.Is(err, errChildSessionParentNotRunning) {
+		t.Errorf("Start() error = %v, want %v", err, errChildSessionParentNotRunning)
+	}
+}
+
+func TestChildSessionBubbleEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		bubble bool
+		want   int
+	}{
+		{name: "bubble", bubble: true, want: 1},
+		{name: "isolated", bubble: false, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := newTestSession(&bootProcessesComponent{})
+			received, running := 0, 0
+			_ = parent.eventbus.Subscribe(func(childEventTest) { received++ })
+			_ = parent.eventbus.Subscribe(func(RunningEvent) { running++ })
+			ph, err := parent.Start(context.Background())
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer func() { _ = ph.Stop(context.Background()) }()
+			child, ch := startChildTest(t, parent, ChildOptions{BubbleEvents: tt.bubble})
+			defer func() { _ = ch.Stop(context.Background()) }()
+			if err := child.eventbus.Publish(childEventTest{}); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			if received != tt.want {
+				t.Errorf("parent received %d events, want %d", received, tt.want)
+			}
+			if running != 1 {
+				t.Errorf("parent received %d running events, want 1", running)
+			}
+		})
+	}
+}
+
+func TestChildSessionStopsBeforeParent(t *testing.T) {
+	var order []string
+	mutex := &sync.Mutex{}
+	parent := newTestSession(&childStopTest{name: "parent", order: &order, mutex: mutex})
+	ph, err := parent.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	_, ch := startChildTest(t, parent, ChildOptions{}, &childStopTest{name: "child", order: &order, mutex: mutex})
+	if err := ph.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	select {
+	case <-ch.Done():
+	default:
+		t.Fatal("child session must be stopped with the parent session")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(order) != 2 || order[0] != "child" || order[1] != "parent" {
+		t.Errorf("stop order = %v, want [child parent]", order)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// bootctl operates a running boot-go session over its control socket.
+//
+//	bootctl [-socket path] components
+//	bootctl [-socket path] shutdown
+//	bootctl [-socket path] log-level debug|info|warn|error|off
+//	bootctl [-socket path] reload
+//	bootctl [-socket path] goroutines
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/boot-go/boot"
+)
+
+// component is the JSON representation of a component returned by the control socket
+type component struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	State        string `json:"state"`
+	InitDuration string `json:"initDuration"`
+	Err          string `json:"error"`
+}
+
+func main() {
+	socket := flag.String("socket", os.Getenv("BOOT_CONTROL_SOCKET"), "path of the control socket")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout for the command")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] components|shutdown|log-level <level>|reload|goroutines\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || *socket == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	request := boot.ControlRequest{Command: flag.Arg(0), Args: flag.Args()[1:]}
+	result, err := send(*socket, *timeout, request)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bootctl: %v\n", err)
+		os.Exit(1)
+	}
+	if err := printResult(request.Command, result); err != nil {
+		fmt.Fprintf(os.Stderr, "bootctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// send writes the request to the control socket and returns the result
+func send(socket string, timeout time.Duration, request boot.ControlRequest) (json.RawMessage, error) {
+	conn, err := net.DialTimeout("unix", socket, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	var response boot.ControlResponse
+	if err := json.Unmarshal(line, &response); err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return response.Result, nil
+}
+
+// printResult writes the result in a human-readable format to stdout
+func printResult(command string, result json.RawMessage) error {
+	switch command {
+	case boot.ControlComponents:
+		var components []component
+		if err := json.Unmarshal(result, &components); err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTYPE\tSTATE\tINIT\tERROR")
+		for _, c := range components {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Name, c.Type, c.State, c.InitDuration, c.Err)
+		}
+		return w.Flush()
+	case boot.ControlGoroutines:
+		var stack string
+		if err := json.Unmarshal(result, &stack); err != nil {
+			return err
+		}
+		fmt.Print(stack)
+	default:
+		fmt.Println("ok")
+	}
+	return nil
+}
@@ -23,28 +23,82 @@
 
 package boot
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
 // componentManager represents a registry entity containing the component with its metadata.
 type componentManager struct {
 	// component is the running global componentManager.
 	component Component
 	// state contains the component state.
-	state componentState
+	state ComponentState
 	// name is used to identify the component.
 	name string
 	// stateChangeMutex to prevent race conditions
 	stateChangeMutex *sync.Mutex
 	// waitGroup is used to block the main process until all Processes are stopped
 	waitGroup *sync.WaitGroup
+	// err contains the last error returned by the component.
+	err error
+	// changed is called after the state has changed. The error is set, if the component failed. It may be nil.
+	changed func(cm *componentManager, state ComponentState, err error)
+	// dependencies contains all wired components.
+	dependencies []*componentManager
+	// initDuration is the time spent in Init()
+	initDuration time.Duration
+	// startTime is set when the process was started
+	startTime time.Time
+	// timeline records the lifecycle steps. It may be nil.
+	timeline *timeline
+	// config contains all injected configuration values
+	config []ConfigValue
+	// lookup returns the configuration value for a key. It may be nil.
+	lookup func(key string) (string, bool)
+	// configKey maps the configuration keys of the component, e.g. for instances. It may be nil.
+	configKey func(key string) string
+	// binding declares additional wired and configured fields. It may be nil.
+	binding *Binding
+	// injected is set when the dependencies and configuration values were injected
+	injected bool
+	// eventbus records the handlers, which the component subscribes in Init. It may be nil.
+	eventbus *eventBus
+	// listeners contains the handlers, which the component subscribed in Init
+	listeners []*busListener
+	// resolving is set while the dependencies of the component are injected to detect dependency cycles
+	resolving bool
+}
+
+// ComponentInfo is a snapshot of a component and its state.
+type ComponentInfo struct {
+	// Name is the name used for the registration, e.g. default
+	Name string
+	// Type is the qualified name of the component type
+	Type string
+	// State is the current state of the component
+	State ComponentState
+	// Dependencies contains the full names of all wired components
+	Dependencies []string
+	// InitDuration is the time spent in Init()
+	InitDuration time.Duration
+	// StartTime is set when the component was started. It is zero for components, which aren't a Process.
+	StartTime time.Time
+	// Err is the last error returned by the component
+	Err error
 }
 
-// componentState is used to describe the current state of a component componentManager
-type componentState int
+// FullName returns the registration name with the type of the component separated by a colon.
+func (i ComponentInfo) FullName() string {
+	return i.Name + ":" + i.Type
+}
+
+// ComponentState is used to describe the current state of a component componentManager
+type ComponentState int
 
 const (
 	// Created is set directly after the component was successfully created by the provided factory
-	Created componentState = iota
+	Created ComponentState = iota
 	// Initialized is set after the component Init() function was called
 	Initialized
 	// Started is set after the component Start() function was called
@@ -55,6 +109,23 @@ const (
 	Failed
 )
 
+// String returns the name of the component state
+func (s ComponentState) String() string {
+	switch s {
+	case Created:
+		return "created"
+	case Initialized:
+		return "initialized"
+	case Started:
+		return "started"
+	case Stopped:
+		return "stopped"
+	case Failed:
+		return "failed"
+	}
+	return "unknown"
+}
+
 func newComponentManager(name string, cmp Component, wg *sync.WaitGroup) *componentManager {
 	return &componentManager{
 		name:             name,
@@ -76,50 +147,200 @@ func (cm *componentManager) getName() string {
 	return QualifiedName(cm.component)
 }
 
+// isInjected returns true, if the dependencies and configuration values were injected or the component
+// isn't created anymore.
+func (cm *componentManager) isInjected() bool {
+	return cm.injected || cm.state != Created
+}
+
+// getState returns the current state and the last error of the component
+func (cm *componentManager) getState() (ComponentState, error) {
+	cm.stateChangeMutex.Lock()
+	defer cm.stateChangeMutex.Unlock()
+	return cm.state, cm.err
+}
+
+// info returns a snapshot of the component
+func (cm *componentManager) info() ComponentInfo {
+	cm.stateChangeMutex.Lock()
+	defer cm.stateChangeMutex.Unlock()
+	var dependencies []string
+	for _, dependency := range cm.dependencies {
+		dependencies = append(dependencies, dependency.getFullName())
+	}
+	return ComponentInfo{
+		Name:         cm.name,
+		Type:         cm.getName(),
+		State:        cm.state,
+		Dependencies: dependencies,
+		InitDuration: cm.initDuration,
+		StartTime:    cm.startTime,
+		Err:          cm.err,
+	}
+}
+
+// addDependency keeps a wired component
+func (cm *componentManager) addDependency(dependency *componentManager) {
+	cm.stateChangeMutex.Lock()
+	defer cm.stateChangeMutex.Unlock()
+	cm.dependencies = append(cm.dependencies, dependency)
+}
+
+// unsubscribe removes the handlers, which the component subscribed in Init, e.g. when it is removed.
+func (cm *componentManager) unsubscribe() {
+	cm.stateChangeMutex.Lock()
+	listeners := cm.listeners
+	cm.listeners = nil
+	cm.stateChangeMutex.Unlock()
+	if cm.eventbus != nil && len(listeners) > 0 {
+		cm.eventbus.removeListeners(listeners)
+	}
+}
+
+// dependencyCycle returns the full names of the components, which depend on each other starting and ending
+// with the component. The last dependency of a resolving component is the one, which is currently resolved.
+func (cm *componentManager) dependencyCycle() []string {
+	names := []string{cm.getFullName()}
+	for next := cm; ; {
+		next.stateChangeMutex.Lock()
+		dependency := next.dependencies[len(next.dependencies)-1]
+		next.stateChangeMutex.Unlock()
+		next = dependency
+		names = append(names, next.getFullName())
+		if next == cm {
+			return names
+		}
+	}
+}
+
+// isDependent returns true, if the component depends on the other component. If live is set, the component
+// must be initialized or started, too.
+func (cm *componentManager) isDependent(other *componentManager, live bool) bool {
+	cm.stateChangeMutex.Lock()
+	defer cm.stateChangeMutex.Unlock()
+	if live && cm.state != Initialized && cm.state != Started {
+		return false
+	}
+	for _, dependency := range cm.dependencies {
+		if dependency == other {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceDependency replaces a wired component
+func (cm *componentManager) replaceDependency(old, replacement *componentManager) {
+	cm.stateChangeMutex.Lock()
+	defer cm.stateChangeMutex.Unlock()
+	for i, dependency := range cm.dependencies {
+		if dependency == old {
+			cm.dependencies[i] = replacement
+		}
+	}
+}
+
+// resolveConfig returns the first key with a configuration value. If no value was found, the first key is
+// returned.
+func (cm *componentManager) resolveConfig(key string, template string) (string, string, bool) {
+	return resolveConfig(configKeys(cm.name, cm.configKey, key, template), cm.lookupConfig)
+}
+
+// lookupConfig returns the configuration value for the key
+func (cm *componentManager) lookupConfig(key string) (string, bool) {
+	if cm.lookup == nil {
+		return getConfig(key)
+	}
+	return cm.lookup(key)
+}
+
+// addConfigValue keeps an injected configuration value
+func (cm *componentManager) addConfigValue(value ConfigValue) {
+	cm.stateChangeMutex.Lock()
+	defer cm.stateChangeMutex.Unlock()
+	value.Component = cm.getFullName()
+	cm.config = append(cm.config, value)
+}
+
+// setState changes the state and notifies the listener
+func (cm *componentManager) setState(state ComponentState, err error) {
+	cm.stateChangeMutex.Lock()
+	cm.state = state
+	if err != nil {
+		cm.err = err
+	}
+	cm.stateChangeMutex.Unlock()
+	cm.notify(state, err)
+}
+
+// notify calls the listener, if one is set. It must be called without holding the stateChangeMutex.
+func (cm *componentManager) notify(state ComponentState, err error) {
+	if cm.changed != nil {
+		cm.changed(cm, state, err)
+	}
+}
+
 // start will call the start function inside Component, if it is not nil
 func (cm *componentManager) start() {
-	if process, ok := cm.component.(Process); ok {
+	process, ok := cm.component.(Process)
+	if !ok {
+		return
+	}
+	cm.stateChangeMutex.Lock()
+	if cm.state != Initialized {
+		cm.stateChangeMutex.Unlock()
+		return
+	}
+	cm.waitGroup.Add(1)
+	cm.state = Started
+	cm.startTime = time.Now()
+	cm.stateChangeMutex.Unlock()
+	cm.notify(Started, nil)
+	go func() {
+		Logger.Debug.Printf("starting %s", cm.getFullName())
+		err := process.Start()
 		cm.stateChangeMutex.Lock()
-		if cm.state == Initialized {
-			cm.waitGroup.Add(1)
-			go func() {
-				cm.stateChangeMutex.Lock()
-				cm.state = Started
-				cm.stateChangeMutex.Unlock()
-				Logger.Debug.Printf("starting %s", cm.getFullName())
-				err := process.Start()
-				cm.stateChangeMutex.Lock()
-				if cm.state == Started {
-					cm.waitGroup.Done()
-					if err == nil {
-						cm.state = Stopped
-					} else {
-						cm.state = Failed
-						Logger.Error.Printf("process.Start() failed: %v", err)
-					}
-				}
-				cm.stateChangeMutex.Unlock()
-			}()
+		if cm.state != Started {
+			// the process was already stopped
+			cm.stateChangeMutex.Unlock()
+			return
+		}
+		state := Stopped
+		if err != nil {
+			state = Failed
+			cm.err = err
+			Logger.Error.Printf("process.Start() failed: %v", err)
 		}
+		cm.state = state
 		cm.stateChangeMutex.Unlock()
-	}
+		cm.notify(state, err)
+		cm.waitGroup.Done()
+	}()
 }
 
 // stop will call the stop function inside Component, if it is not nil
 func (cm *componentManager) stop() {
-	if process, ok := cm.component.(Process); ok {
-		cm.stateChangeMutex.Lock()
-		if cm.state == Started {
-			Logger.Debug.Printf("stopping %s", cm.getFullName())
-			err := process.Stop()
-			if err != nil {
-				Logger.Error.Printf("process.Stop() failed: %v", err)
-			}
-			cm.state = Stopped
-			cm.waitGroup.Done()
-		}
+	process, ok := cm.component.(Process)
+	if !ok {
+		return
+	}
+	cm.stateChangeMutex.Lock()
+	if cm.state != Started {
 		cm.stateChangeMutex.Unlock()
+		return
+	}
+	Logger.Debug.Printf("stopping %s", cm.getFullName())
+	stopStart := time.Now()
+	err := process.Stop()
+	cm.timeline.record(cm.getFullName(), StopStep, stopStart)
+	if err != nil {
+		cm.err = err
+		Logger.Error.Printf("process.Stop() failed: %v", err)
 	}
+	cm.state = Stopped
+	cm.stateChangeMutex.Unlock()
+	cm.notify(Stopped, err)
+	cm.waitGroup.Done()
 }
 
 type componentManagers []*componentManager
@@ -46,7 +46,7 @@ func (c *componentManagerTest) Stop() error {
 func TestComponentManagerStart(t *testing.T) {
 	type fields struct {
 		component        Component
-		state            componentState
+		state            ComponentState
 		name             string
 		stateChangeMutex *sync.Mutex
 		waitGroup        *sync.WaitGroup
@@ -57,7 +57,7 @@ func TestComponentManagerStart(t *testing.T) {
 	}{
 		{name: "with error", fields: struct {
 			component        Component
-			state            componentState
+			state            ComponentState
 			name             string
 			stateChangeMutex *sync.Mutex
 			waitGroup        *sync.WaitGroup
@@ -80,7 +80,7 @@ func TestComponentManagerStart(t *testing.T) {
 func TestComponentManagerStop(t *testing.T) {
 	type fields struct {
 		component        Component
-		state            componentState
+		state            ComponentState
 		name             string
 		stateChangeMutex *sync.Mutex
 		waitGroup        *sync.WaitGroup
@@ -91,7 +91,7 @@ func TestComponentManagerStop(t *testing.T) {
 	}{
 		{name: "with error", fields: struct {
 			component        Component
-			state            componentState
+			state            ComponentState
 			name             string
 			stateChangeMutex *sync.Mutex
 			waitGroup        *sync.WaitGroup
@@ -111,3 +111,24 @@ func TestComponentManagerStop(t *testing.T) {
 		})
 	}
 }
+
+func TestComponentStateString(t *testing.T) {
+	tests := []struct {
+		state ComponentState
+		want  string
+	}{
+		{state: Created, want: "created"},
+		{state: Initialized, want: "initialized"},
+		{state: Started, want: "started"},
+		{state: Stopped, want: "stopped"},
+		{state: Failed, want: "failed"},
+		{state: -1, want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.state.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
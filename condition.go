@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"reflect"
+)
+
+// Condition decides at boot, whether a registration is used. All conditions of a registration must match.
+// Conditions on flags and configuration values are evaluated first. Conditions on other components are
+// evaluated afterwards in the order of the registrations, so they see all components without such
+// conditions and all conditional components registered before.
+type Condition struct {
+	// description is used for logging the decision
+	description string
+	// component is true, if the condition depends on other registrations
+	component bool
+	matches   func(ctx *conditionContext) bool
+}
+
+// conditionContext contains the facts, which are used to evaluate the conditions
+type conditionContext struct {
+	flags    []Flag
+	registry *registry
+	lookup   func(key string) (string, bool)
+}
+
+// OnFlag matches, if the flag is set, e.g. OnFlag(UnitTestFlag)
+func OnFlag(flag Flag) Condition {
+	return Condition{
+		description: "flag " + string(flag) + " is set",
+		matches: func(ctx *conditionContext) bool {
+			return ctx.hasFlag(flag)
+		},
+	}
+}
+
+// OnMissingFlag matches, if the flag is not set
+func OnMissingFlag(flag Flag) Condition {
+	return Condition{
+		description: "flag " + string(flag) + " is not set",
+		matches: func(ctx *conditionContext) bool {
+			return !ctx.hasFlag(flag)
+		},
+	}
+}
+
+// OnConfig matches, if the configuration value of the key is equal to the value
+func OnConfig(key, value string) Condition {
+	return Condition{
+		description: "configuration " + key + " is " + value,
+		matches: func(ctx *conditionContext) bool {
+			cfgValue, ok := ctx.lookup(key)
+			return ok && cfgValue == value
+		},
+	}
+}
+
+// OnComponent matches, if a component of the given type or implementing the given interface is registered.
+// The type is provided by a nil pointer, e.g. OnComponent((*EventBus)(nil)) or OnComponent((*hello)(nil))
+func OnComponent(v any) Condition {
+	t := conditionType(v)
+	return Condition{
+		description: "component " + t.String() + " is registered",
+		component:   true,
+		matches: func(ctx *conditionContext) bool {
+			return ctx.registry.has(t)
+		},
+	}
+}
+
+// OnMissingComponent matches, if no component of the given type or implementing the given interface is
+// registered. The type is provided the same way as for OnComponent.
+func OnMissingComponent(v any) Condition {
+	t := conditionType(v)
+	return Condition{
+		description: "component " + t.String() + " is not registered",
+		component:   true,
+		matches: func(ctx *conditionContext) bool {
+			return !ctx.registry.has(t)
+		},
+	}
+}
+
+// conditionType returns the interface type for a pointer to an interface, otherwise the type itself
+func conditionType(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+		return t.Elem()
+	}
+	return t
+}
+
+func (ctx *conditionContext) hasFlag(flag Flag) bool {
+	for _, f := range ctx.flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// hasComponentConditions returns true, if any condition depends on other registrations
+func (f factory) hasComponentConditions() bool {
+	for _, c := range f.conditions {
+		if c.component {
+			return true
+		}
+	}
+	return false
+}
+
+// matches evaluates all conditions of the given kind and logs the decision, when all conditions are evaluated
+func (f factory) matches(ctx *conditionContext, component bool) bool {
+	if len(f.conditions) == 0 {
+		return true
+	}
+	for _, c := range f.conditions {
+		if c.component != component {
+			continue
+		}
+		if !c.matches(ctx) {
+			Logger.Info.Printf("registration %s:%s skipped - condition not matched: %s", f.name, f.qualifiedName(), c.description)
+			return false
+		}
+	}
+	if component || !f.hasComponentConditions() {
+		Logger.Info.Printf("registration %s:%s accepted", f.name, f.qualifiedName())
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"testing"
+)
+
+type conditionTest struct{}
+
+func (c *conditionTest) Init() error { return nil }
+
+type conditionMockTest struct{}
+
+func (c *conditionMockTest) Init() error { return nil }
+
+func TestSessionRegisterConditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		flags      []Flag
+		env        map[string]string
+		conditions []Condition
+		want       bool
+	}{
+		{name: "no condition", want: true},
+		{name: "flag set", flags: []Flag{UnitTestFlag}, conditions: []Condition{OnFlag(UnitTestFlag)}, want: true},
+		{name: "flag not set", conditions: []Condition{OnFlag(UnitTestFlag)}, want: false},
+		{name: "configured flag set", env: map[string]string{"BOOT_FLAGS": "canary"}, conditions: []Condition{OnFlag("canary")}, want: true},
+		{name: "flag missing", conditions: []Condition{OnMissingFlag(UnitTestFlag)}, want: true},
+		{name: "flag not missing", flags: []Flag{UnitTestFlag}, conditions: []Condition{OnMissingFlag(UnitTestFlag)}, want: false},
+		{name: "config matches", env: map[string]string{"CONDITION_TEST": "on"}, conditions: []Condition{OnConfig("CONDITION_TEST", "on")}, want: true},
+		{name: "config differs", env: map[string]string{"CONDITION_TEST": "off"}, conditions: []Condition{OnConfig("CONDITION_TEST", "on")}, want: false},
+		{name: "config missing", conditions: []Condition{OnConfig("CONDITION_TEST_MISSING", "on")}, want: false},
+		{name: "interface registered", conditions: []Condition{OnComponent((*EventBus)(nil))}, want: true},
+		{name: "interface missing", conditions: []Condition{OnMissingComponent((*EventBus)(nil))}, want: false},
+		{name: "component registered", conditions: []Condition{OnComponent((*conditionMockTest)(nil))}, want: true},
+		{name: "component missing", conditions: []Condition{OnMissingComponent((*conditionMockTest)(nil))}, want: false},
+		{name: "all match", flags: []Flag{UnitTestFlag}, conditions: []Condition{OnFlag(UnitTestFlag), OnComponent((*Runtime)(nil))}, want: true},
+		{name: "one fails", flags: []Flag{UnitTestFlag}, conditions: []Condition{OnFlag(UnitTestFlag), OnMissingComponent((*Runtime)(nil))}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			s := NewSession(tt.flags...)
+			// the conditional registration is done first, so the order doesn't matter
+			if err := s.Register(func() Component { return &conditionTest{} }, tt.conditions...); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			if err := s.Register(func() Component { return &conditionMockTest{} }); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			reg, err := s.createComponents()
+			if err != nil {
+				t.Fatalf("createComponents() error = %v", err)
+			}
+			if got := reg.has(conditionType((*conditionTest)(nil))); got != tt.want {
+				t.Errorf("component registered = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionOverrideConditions(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags []Flag
+		want  string
+	}{
+		{name: "unit test", flags: []Flag{UnitTestFlag}, want: "github.com/boot-go/boot/conditionMockTest"},
+		{name: "production", want: "github.com/boot-go/boot/conditionTest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSession(tt.flags...)
+			_ = s.RegisterName("db", func() Component { return &conditionTest{} }, OnMissingFlag(UnitTestFlag))
+			_ = s.RegisterName("db", func() Component { return &conditionMockTest{} }, OnFlag(UnitTestFlag))
+			reg, err := s.createComponents()
+			if err != nil {
+				t.Fatalf("createComponents() error = %v", err)
+			}
+			found := ""
+			for id, entries := range reg.items {
+				if _, ok := entries["db"]; ok {
+					found = id
+				}
+			}
+			if found != tt.want {
+				t.Errorf("registered %s, want %s", found, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strings"
+	"sync"
+)
+
+// The commands supported by the control socket.
+const (
+	// ControlComponents lists all components with their state.
+	ControlComponents = "components"
+	// ControlShutdown shuts the session down gracefully.
+	ControlShutdown = "shutdown"
+	// ControlLogLevel changes the log level of the Logger. The level is the first argument.
+	ControlLogLevel = "log-level"
+	// ControlReload publishes a ReloadEvent, so components can reload their configuration.
+	ControlReload = "reload"
+	// ControlGoroutines returns the stack of all goroutines.
+	ControlGoroutines = "goroutines"
+)
+
+// ControlRequest is sent to the control socket as a single line of JSON.
+type ControlRequest struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// ControlResponse is returned by the control socket as a single line of JSON for every request.
+type ControlResponse struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// control is an optional component, which allows to operate the session over a unix domain socket.
+type control struct {
+	// Socket is the path of the unix domain socket. The default is <tmp>/<program>.sock
+	Socket   string `boot:"config,key:BOOT_CONTROL_SOCKET,default:''"`
+	session  *Session
+	mutex    sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	handlers sync.WaitGroup
+	stopped  bool
+}
+
+var _ Process = (*control)(nil) // Verify conformity to Process
+
+func newControl(s *Session) *control {
+	return &control{
+		session: s,
+		conns:   make(map[net.Conn]struct{}),
+	}
+}
+
+// Init is described in the Component interface
+func (c *control) Init() error {
+	if c.Socket == "" {
+		c.Socket = filepath.Join(os.TempDir(), filepath.Base(os.Args[0])+".sock")
+	}
+	return nil
+}
+
+// Start is described in the Process interface
+func (c *control) Start() error {
+	// a socket file is left over, when the previous process was killed
+	if info, err := os.Stat(c.Socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(c.Socket)
+	}
+	listener, err := net.Listen("unix", c.Socket)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(c.Socket, 0o600); err != nil {
+		_ = listener.Close()
+		return err
+	}
+	c.mutex.Lock()
+	if c.stopped {
+		c.mutex.Unlock()
+		return listener.Close()
+	}
+	c.listener = listener
+	c.mutex.Unlock()
+	Logger.Info.Printf("control socket listening on %s", c.Socket)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		c.mutex.Lock()
+		if c.stopped {
+			c.mutex.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		c.conns[conn] = struct{}{}
+		c.handlers.Add(1)
+		c.mutex.Unlock()
+		go c.serve(conn)
+	}
+}
+
+// Stop is described in the Process interface
+func (c *control) Stop() error {
+	c.mutex.Lock()
+	c.stopped = true
+	var err error
+	if c.listener != nil {
+		err = c.listener.Close()
+	}
+	for conn := range c.conns {
+		_ = conn.Close()
+	}
+	c.mutex.Unlock()
+	c.handlers.Wait()
+	return err
+}
+
+// serve processes all requests of a connection until it is closed
+func (c *control) serve(conn net.Conn) {
+	defer c.handlers.Done()
+	defer func() {
+		c.mutex.Lock()
+		delete(c.conns, conn)
+		c.mutex.Unlock()
+		_ = conn.Close()
+	}()
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var request ControlRequest
+		var response ControlResponse
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			response.Error = "invalid request: " + err.Error()
+		} else {
+			response = c.execute(request)
+		}
+		if err := encoder.Encode(response); err != nil {
+			Logger.Error.Printf("control response failed: %v", err)
+			return
+		}
+	}
+}
+
+// execute runs the command of the request
+func (c *control) execute(request ControlRequest) ControlResponse {
+	Logger.Info.Printf("control command %s %s", request.Command, strings.Join(request.Args, " "))
+	var result any
+	var err error
+	switch request.Command {
+	case ControlComponents:
+		result = newAdminComponents(c.session.Components())
+	case ControlShutdown:
+		err = c.session.Shutdown()
+	case ControlLogLevel:
+		if len(request.Args) != 1 {
+			err = fmt.Errorf("%s requires the level as argument", ControlLogLevel)
+		} else {
+			err = Logger.SetLevel(request.Args[0])
+		}
+	case ControlReload:
+		c.session.publishEvent(ReloadEvent{})
+	case ControlGoroutines:
+		var b strings.Builder
+		err = pprof.Lookup("goroutine").WriteTo(&b, 1)
+		result = b.String()
+	default:
+		err = fmt.Errorf("unknown command %s", request.Command)
+	}
+	response := ControlResponse{}
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+	if result != nil {
+		response.Result, err = json.Marshal(result)
+		if err != nil {
+			response.Error = err.Error()
+		}
+	}
+	return response
+}
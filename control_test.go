@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type controlReloadTest struct {
+	Eventbus EventBus `boot:"wire"`
+	reloaded chan struct{}
+}
+
+func (c *controlReloadTest) Init() error {
+	return c.Eventbus.Subscribe(func(event ReloadEvent) {
+		c.reloaded <- struct{}{}
+	})
+}
+
+func TestControlCommands(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "boot.sock")
+	t.Setenv("BOOT_CONTROL_SOCKET", socket)
+	defer func() {
+		_ = Logger.SetLevel("info")
+	}()
+	reload := &controlReloadTest{reloaded: make(chan struct{}, 1)}
+	c := newControl(nil)
+	s := newTestSession(c, reload)
+	c.session = s.Session
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	var conn net.Conn
+	for i := 0; conn == nil; i++ {
+		if conn, err = net.Dial("unix", socket); err != nil && i > 100 {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	send := func(request string) ControlResponse {
+		t.Helper()
+		if _, err := conn.Write([]byte(request + "\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("ReadBytes() error = %v", err)
+		}
+		var response ControlResponse
+		if err := json.Unmarshal(line, &response); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		return response
+	}
+	tests := []struct {
+		name    string
+		request string
+		result  string
+		err     string
+	}{
+		{name: "components", request: `{"command":"components"}`, result: `controlReloadTest"`},
+		{name: "log-level", request: `{"command":"log-level","args":["warn"]}`},
+		{name: "log-level without level", request: `{"command":"log-level"}`, err: "requires the level"},
+		{name: "log-level unknown", request: `{"command":"log-level","args":["trace"]}`, err: "unknown log level"},
+		{name: "reload", request: `{"command":"reload"}`},
+		{name: "goroutines", request: `{"command":"goroutines"}`, result: "goroutine profile"},
+		{name: "unknown", request: `{"command":"restart"}`, err: "unknown command"},
+		{name: "invalid", request: `restart`, err: "invalid request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := send(tt.request)
+			if !strings.Contains(response.Error, tt.err) || (tt.err == "" && response.Error != "") {
+				t.Errorf("Error = %q, want %q", response.Error, tt.err)
+			}
+			if !strings.Contains(string(response.Result), tt.result) {
+				t.Errorf("Result = %s, want %s", response.Result, tt.result)
+			}
+		})
+	}
+	select {
+	case <-reload.reloaded:
+	case <-time.After(time.Second):
+		t.Error("ReloadEvent not published")
+	}
+	if Logger.Info.Writer() == Logger.Warn.Writer() {
+		t.Error("log level not changed")
+	}
+	if response := send(`{"command":"shutdown"}`); response.Error != "" {
+		t.Errorf("shutdown error = %s", response.Error)
+	}
+	select {
+	case <-h.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("session not stopped after shutdown")
+	}
+	if err := h.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+}
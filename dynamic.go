@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	errSessionNotRunning      = errors.New("session is not running")
+	errComponentNotFound      = errors.New("component not found")
+	errComponentHasDependents = errors.New("component is used by other components")
+)
+
+// AddComponent creates a component while the session is running, e.g. when a tenant is enabled or a plugin
+// was discovered. The component is wired, initialized and started like the components created on boot.
+// It is stopped on shutdown or when it is removed with RemoveComponent. The handlers, which the component
+// subscribed in Init, are unsubscribed when it is removed or if it can't be added.
+func (s *Session) AddComponent(name string, create func() Component) error {
+	if name == "" || create == nil {
+		return errSessionRegisterNameOrFunction
+	}
+	defer s.dynamicMutex.Unlock()
+	s.dynamicMutex.Lock()
+	reg, h, err := s.running()
+	if err != nil {
+		return err
+	}
+	component, err := factory{create: create, name: name}.newComponent(nil)
+	if err != nil {
+		return err
+	}
+	if err := reg.addItem(name, false, component); err != nil {
+		return err
+	}
+	cm := reg.item(name + ":" + QualifiedName(component))
+	if _, err := resolveDependency(cm, reg); err != nil {
+		reg.removeItem(cm)
+		return err
+	}
+	if err := h.add(cm); err != nil {
+		cm.unsubscribe()
+		reg.removeItem(cm)
+		return err
+	}
+	Logger.Info.Printf("component %s added", cm.getFullName())
+	return nil
+}
+
+// RemoveComponent stops, unsubscribes and removes the component with the full name, e.g. default:github.com/boot-go/boot/eventBus,
+// while the session is running. The removal is rejected, if other initialized or started components depend
+// on the component, including the components of child sessions. Like a process, which returns from Start,
+// removing the last started process ends a session, which handles signals, e.g. one created with NewSession.
+func (s *Session) RemoveComponent(fullName string) error {
+	defer s.dynamicMutex.Unlock()
+	s.dynamicMutex.Lock()
+	reg, h, err := s.running()
+	if err != nil {
+		return err
+	}
+	cm := reg.item(fullName)
+	if cm == nil {
+		return fmt.Errorf("%w: %s", errComponentNotFound, fullName)
+	}
+	if dependents := s.dependents(cm); len(dependents) > 0 {
+		return fmt.Errorf("%w: %s", errComponentHasDependents, strings.Join(dependents, ", "))
+	}
+	if err := h.remove(cm); err != nil {
+		return err
+	}
+	cm.stop()
+	cm.unsubscribe()
+	reg.removeItem(cm)
+	Logger.Info.Printf("component %s removed", fullName)
+	return nil
+}
+
+// ReplaceComponent replaces the component with the full name by a new instance while the session is running,
+// e.g. when the url of a database was rotated. The replacement is created, wired and initialized first. If
+// this fails or the session is stopping, the old component remains untouched and the replacement is
+// discarded, i.e. it is stopped and the handlers, which it subscribed while it was initialized, are
+// unsubscribed. Otherwise, all wired Ref fields, which reference the old component, are re-pointed to the
+// replacement, the replacement is started and the old component is stopped and unsubscribed. The replacement is rejected, if
+// a live component references the old component by a field, which isn't a Ref, because the field can't be
+// changed safely while the component is running.
+func (s *Session) ReplaceComponent(fullName string, create func() Component) error {
+	if create == nil {
+		return errSessionRegisterNameOrFunction
+	}
+	defer s.dynamicMutex.Unlock()
+	s.dynamicMutex.Lock()
+	reg, h, err := s.running()
+	if err != nil {
+		return err
+	}
+	if h.isStopping() {
+		return errSessionNotRunning
+	}
+	old := reg.item(fullName)
+	if old == nil {
+		return fmt.Errorf("%w: %s", errComponentNotFound, fullName)
+	}
+	component, err := factory{create: create, name: old.name}.newComponent(nil)
+	if err != nil {
+		return err
+	}
+	replacement := reg.newItem(old.name, component)
+	// all references are collected before the replacement is initialized, so nothing has to be undone if one
+	// of them can't be assigned
+	wired := s.wiredComponents(old)
+	refs := make([][]reference, len(wired))
+	for i, cm := range wired {
+		if refs[i], err = wiredReferences(cm, old.component, component); err != nil {
+			return err
+		}
+	}
+	if _, err := resolveDependency(replacement, reg); err != nil {
+		discard(replacement)
+		return err
+	}
+	err = h.replace(old, replacement, func() {
+		for i, cm := range wired {
+			for _, ref := range refs[i] {
+				ref.set(component)
+			}
+			cm.replaceDependency(old, replacement)
+		}
+		reg.replaceItem(old, replacement)
+	})
+	if err != nil {
+		discard(replacement)
+		return err
+	}
+	old.stop()
+	old.unsubscribe()
+	Logger.Info.Printf("component %s replaced by %s", fullName, replacement.getFullName())
+	return nil
+}
+
+// discard stops a replacement, which wasn't put in place, and unsubscribes the handlers it subscribed while
+// it was initialized.
+func discard(replacement *componentManager) {
+	replacement.stop()
+	replacement.unsubscribe()
+	Logger.Debug.Printf("replacement %s discarded", replacement.getFullName())
+}
+
+// running returns the registry and the handle, if the session is running.
+func (s *Session) running() (*registry, *Handle, error) {
+	s.changeMutex.Lock()
+	defer s.changeMutex.Unlock()
+	if s.phase != running || s.registry == nil || s.handle == nil {
+		return nil, nil, errSessionNotRunning
+	}
+	return s.registry, s.handle, nil
+}
+
+// dependents returns the full names of all live components, which depend on the component. The components
+// of the child sessions are included.
+func (s *Session) dependents(cm *componentManager) []string {
+	s.changeMutex.Lock()
+	reg := s.registry
+	s.changeMutex.Unlock()
+	var dependents []string
+	if reg != nil {
+		for _, entry := range reg.all() {
+			if entry.isDependent(cm, true) {
+				dependents = append(dependents, entry.getFullName())
+			}
+		}
+	}
+	for _, child := range s.childSessions() {
+		dependents = append(dependents, child.dependents(cm)...)
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// wiredComponents returns all components, which depend on the component. The components of the child sessions
+// are included.
+func (s *Session) wiredComponents(cm *componentManager) []*componentManager {
+	s.changeMutex.Lock()
+	reg := s.registry
+	s.changeMutex.Unlock()
+	var wired []*componentManager
+	if reg != nil {
+		for _, entry := range reg.all() {
+			if entry.isDependent(cm, false) {
+				wired = append(wired, entry)
+			}
+		}
+	}
+	for _, child := range s.childSessions() {
+		wired = append(wired, child.wiredComponents(cm)...)
+	}
+	return wired
+}
+
+// childSessions returns all running child sessions.
+func (s *Session) childSessions() []*Session {
+	s.children.mutex.Lock()
+	defer s.children.mutex.Unlock()
+	sessions := make([]*Session, 0, len(s.children.handles))
+	for _, h := range s.children.handles {
+		sessions = append(sessions, h.session)
+	}
+	return sessions
+}
@@ -0,0 +1,426 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type dynamicProviderTest struct{}
+
+func (c *dynamicProviderTest) Init() error { return nil }
+
+type dynamicProcessTest struct {
+	Provider Ref[*dynamicProviderTest] `boot:"wire"`
+	Eventbus EventBus                  `boot:"wire"`
+	block    chan struct{}
+	initErr  error
+}
+
+func (c *dynamicProcessTest) Init() error {
+	c.block = make(chan struct{})
+	return c.initErr
+}
+
+func (c *dynamicProcessTest) Start() error {
+	<-c.block
+	return nil
+}
+
+func (c *dynamicProcessTest) Stop() error {
+	close(c.block)
+	return nil
+}
+
+const (
+	dynamicProviderName = "default:github.com/boot-go/boot/dynamicProviderTest"
+	dynamicProcessName  = "default:github.com/boot-go/boot/dynamicProcessTest"
+)
+
+func startDynamicTest(t *testing.T) (*testSession, *Handle) {
+	t.Helper()
+	s := newTestSession(&bootProcessesComponent{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := s.AddComponent(DefaultName, func() Component { return &dynamicProviderTest{} }); err != nil {
+		t.Fatalf("AddComponent() error = %v", err)
+	}
+	return s, h
+}
+
+func componentState(s *Session, fullName string) (ComponentState, bool) {
+	for _, info := range s.Components() {
+		if info.FullName() == fullName {
+			return info.State, true
+		}
+	}
+	return Failed, false
+}
+
+func TestSessionAddComponent(t *testing.T) {
+	s, h := startDynamicTest(t)
+	process := &dynamicProcessTest{}
+	if err := s.AddComponent(DefaultName, func() Component { return process }); err != nil {
+		t.Fatalf("AddComponent() error = %v", err)
+	}
+	if process.Provider.Get() == nil || process.Eventbus != s.eventbus {
+		t.Errorf("AddComponent() must wire the dependencies")
+	}
+	if state, _ := componentState(s.Session, dynamicProcessName); state != Started {
+		t.Errorf("component state = %s, want %s", state, Started)
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if state, _ := componentState(s.Session, dynamicProcessName); state != Stopped {
+		t.Errorf("component state = %s, want %s", state, Stopped)
+	}
+}
+
+func TestSessionAddComponentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmpName string
+		create  func() Component
+		want    error
+	}{
+		{name: "missing name", create: func() Component { return &dynamicProcessTest{} }, want: errSessionRegisterNameOrFunction},
+		{name: "missing function", cmpName: DefaultName, want: errSessionRegisterNameOrFunction},
+		{name: "init failed", cmpName: DefaultName, create: func() Component { return &dynamicProcessTest{initErr: errors.New("fail")} }, want: ErrInitialization},
+		{name: "wiring failed", cmpName: DefaultName, create: func() Component { return &childConsumerTest{} }, want: ErrInjection},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, h := startDynamicTest(t)
+			if err := s.AddComponent(tt.cmpName, tt.create); !errors.Is(err, tt.want) {
+				t.Errorf("AddComponent() error = %v, want %v", err, tt.want)
+			}
+			if tt.create != nil {
+				if _, ok := componentState(s.Session, tt.cmpName+":"+QualifiedName(tt.create())); ok {
+					t.Errorf("failed component must not be registered")
+				}
+			}
+			if err := h.Stop(context.Background()); err != nil {
+				t.Errorf("Stop() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestSessionAddComponentNotRunning(t *testing.T) {
+	s := newTestSession()
+	if err := s.AddComponent(DefaultName, func() Component { return &dynamicProviderTest{} }); !errors.Is(err, errSessionNotRunning) {
+		t.Errorf("AddComponent() error = %v, want %v", err, errSessionNotRunning)
+	}
+	if err := s.RemoveComponent(dynamicProviderName); !errors.Is(err, errSessionNotRunning) {
+		t.Errorf("RemoveComponent() error = %v, want %v", err, errSessionNotRunning)
+	}
+}
+
+func TestSessionRemoveComponent(t *testing.T) {
+	s, h := startDynamicTest(t)
+	defer func() { _ = h.Stop(context.Background()) }()
+	if err := s.AddComponent(DefaultName, func() Component { return &dynamicProcessTest{} }); err != nil {
+		t.Fatalf("AddComponent() error = %v", err)
+	}
+	if err := s.RemoveComponent(dynamicProviderName); !errors.Is(err, errComponentHasDependents) {
+		t.Errorf("RemoveComponent() error = %v, want %v", err, errComponentHasDependents)
+	}
+	if err := s.RemoveComponent(dynamicProcessName); err != nil {
+		t.Fatalf("RemoveComponent() error = %v", err)
+	}
+	if _, ok := componentState(s.Session, dynamicProcessName); ok {
+		t.Errorf("removed component must not be listed")
+	}
+	if err := s.RemoveComponent(dynamicProviderName); err != nil {
+		t.Errorf("RemoveComponent() error = %v", err)
+	}
+	if err := s.RemoveComponent(dynamicProviderName); !errors.Is(err, errComponentNotFound) {
+		t.Errorf("RemoveComponent() error = %v, want %v", err, errComponentNotFound)
+	}
+}
+
+func TestSessionRemoveComponentUsedByChild(t *testing.T) {
+	s, h := startDynamicTest(t)
+	defer func() { _ = h.Stop(context.Background()) }()
+	_, ch := startChildTest(t, s, ChildOptions{}, &dynamicProcessTest{})
+	if err := s.RemoveComponent(dynamicProviderName); !errors.Is(err, errComponentHasDependents) {
+		t.Errorf("RemoveComponent() error = %v, want %v", err, errComponentHasDependents)
+	}
+	if err := ch.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := s.RemoveComponent(dynamicProviderName); err != nil {
+		t.Errorf("RemoveComponent() error = %v", err)
+	}
+}
+
+func TestSessionRemoveLastProcess(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		wantEnd bool
+	}{
+		{name: "signals", options: Options{Signals: DefaultSignals()}, wantEnd: true},
+		{name: "without signals", options: Options{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSessionWithOptions(tt.options, &bootProcessesComponent{})
+			h, err := s.Start(context.Background())
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer func() { _ = h.Stop(context.Background()) }()
+			if err := s.RemoveComponent(DefaultName + ":" + QualifiedName(&bootProcessesComponent{})); err != nil {
+				t.Fatalf("RemoveComponent() error = %v", err)
+			}
+			select {
+			case <-h.Done():
+				if !tt.wantEnd {
+					t.Error("session without signals must run until it is stopped")
+				}
+			case <-time.After(time.Second):
+				if tt.wantEnd {
+					t.Error("removing the last process must end the session")
+				}
+			}
+		})
+	}
+}
+
+type dynamicOtherProviderTest struct{}
+
+func (c *dynamicOtherProviderTest) Init() error { return nil }
+
+const dynamicOtherProviderName = "default:github.com/boot-go/boot/dynamicOtherProviderTest"
+
+// dynamicPlainTest wires a component without a Ref, so it can't be replaced
+type dynamicPlainTest struct {
+	Provider *dynamicOtherProviderTest `boot:"wire"`
+}
+
+func (c *dynamicPlainTest) Init() error { return nil }
+
+func TestSessionReplaceComponent(t *testing.T) {
+	s, h := startDynamicTest(t)
+	defer func() { _ = h.Stop(context.Background()) }()
+	process := &dynamicProcessTest{}
+	if err := s.AddComponent(DefaultName, func() Component { return process }); err != nil {
+		t.Fatalf("AddComponent() error = %v", err)
+	}
+	provider := &dynamicProviderTest{}
+	if err := s.ReplaceComponent(dynamicProviderName, func() Component { return provider }); err != nil {
+		t.Fatalf("ReplaceComponent() error = %v", err)
+	}
+	if process.Provider.Get() != provider {
+		t.Errorf("wired reference must refer to the replacement")
+	}
+	replacement := &dynamicProcessTest{}
+	if err := s.ReplaceComponent(dynamicProcessName, func() Component { return replacement }); err != nil {
+		t.Fatalf("ReplaceComponent() error = %v", err)
+	}
+	select {
+	case <-process.block:
+	default:
+		t.Errorf("replaced process must be stopped")
+	}
+	if replacement.Provider.Get() != provider {
+		t.Errorf("replacement must be wired")
+	}
+	if state, _ := componentState(s.Session, dynamicProcessName); state != Started {
+		t.Errorf("replacement state = %s, want %s", state, Started)
+	}
+	if err := s.RemoveComponent(dynamicProviderName); !errors.Is(err, errComponentHasDependents) {
+		t.Errorf("RemoveComponent() error = %v, want %v", err, errComponentHasDependents)
+	}
+}
+
+func TestSessionReplaceComponentConcurrently(t *testing.T) {
+	s, h := startDynamicTest(t)
+	defer func() { _ = h.Stop(context.Background()) }()
+	process := &dynamicProcessTest{}
+	if err := s.AddComponent(DefaultName, func() Component { return process }); err != nil {
+		t.Fatalf("AddComponent() error = %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			if process.Provider.Get() == nil {
+				t.Error("wired reference must never be nil")
+				return
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		if err := s.ReplaceComponent(dynamicProviderName, func() Component { return &dynamicProviderTest{} }); err != nil {
+			t.Fatalf("ReplaceComponent() error = %v", err)
+		}
+	}
+	<-done
+}
+
+func TestSessionReplaceComponentErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fullName string
+		create   func() Component
+		want     error
+	}{
+		{name: "missing function", fullName: dynamicProviderName, want: errSessionRegisterNameOrFunction},
+		{name: "not found", fullName: "default:unknown", create: func() Component { return &dynamicProviderTest{} }, want: errComponentNotFound},
+		{name: "init failed", fullName: dynamicProcessName, create: func() Component { return &dynamicProcessTest{initErr: errors.New("fail")} }, want: ErrInitialization},
+		{name: "not assignable", fullName: dynamicProviderName, create: func() Component { return &dynamicOtherProviderTest{} }, want: ErrInjection},
+		{name: "not a reference", fullName: dynamicOtherProviderName, create: func() Component { return &dynamicOtherProviderTest{} }, want: ErrInjection},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, h := startDynamicTest(t)
+			process := &dynamicProcessTest{}
+			if err := s.AddComponent(DefaultName, func() Component { return process }); err != nil {
+				t.Fatalf("AddComponent() error = %v", err)
+			}
+			if err := s.AddComponent(DefaultName, func() Component { return &dynamicOtherProviderTest{} }); err != nil {
+				t.Fatalf("AddComponent() error = %v", err)
+			}
+			if err := s.AddComponent(DefaultName, func() Component { return &dynamicPlainTest{} }); err != nil {
+				t.Fatalf("AddComponent() error = %v", err)
+			}
+			provider := process.Provider.Get()
+			if err := s.ReplaceComponent(tt.fullName, tt.create); !errors.Is(err, tt.want) {
+				t.Errorf("ReplaceComponent() error = %v, want %v", err, tt.want)
+			}
+			if process.Provider.Get() != provider {
+				t.Errorf("wired reference must not be changed")
+			}
+			if state, _ := componentState(s.Session, dynamicProcessName); state != Started {
+				t.Errorf("process state = %s, want %s", state, Started)
+			}
+			if err := h.Stop(context.Background()); err != nil {
+				t.Errorf("Stop() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+type dynamicEventTest struct{}
+
+// dynamicSubscriberTest subscribes while it is initialized and calls the hook afterwards
+type dynamicSubscriberTest struct {
+	Eventbus EventBus `boot:"wire"`
+	hook     func() error
+}
+
+func (c *dynamicSubscriberTest) Init() error {
+	if err := c.Eventbus.Subscribe(func(dynamicEventTest) {}); err != nil {
+		return err
+	}
+	if c.hook != nil {
+		return c.hook()
+	}
+	return nil
+}
+
+const dynamicSubscriberName = "default:github.com/boot-go/boot/dynamicSubscriberTest"
+
+func TestSessionAddRemoveComponentUnsubscribe(t *testing.T) {
+	tests := []struct {
+		name   string
+		hook   func() error
+		remove bool
+		want   error
+	}{
+		{name: "removed", remove: true},
+		{name: "init failed", hook: func() error { return errors.New("fail") }, want: ErrInitialization},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, h := startDynamicTest(t)
+			defer func() { _ = h.Stop(context.Background()) }()
+			err := s.AddComponent(DefaultName, func() Component { return &dynamicSubscriberTest{hook: tt.hook} })
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("AddComponent() error = %v, want %v", err, tt.want)
+			}
+			if tt.remove {
+				if err := s.RemoveComponent(dynamicSubscriberName); err != nil {
+					t.Fatalf("RemoveComponent() error = %v", err)
+				}
+			}
+			if got := len(s.eventbus.subscriptions()[QualifiedName(dynamicEventTest{})]); got != 0 {
+				t.Errorf("%d handlers subscribed, want 0", got)
+			}
+		})
+	}
+}
+
+func TestSessionReplaceComponentDiscard(t *testing.T) {
+	tests := []struct {
+		name string
+		hook func(h *Handle) error
+		want error
+	}{
+		{name: "replaced", hook: func(h *Handle) error { return nil }},
+		{name: "init failed", hook: func(h *Handle) error { return errors.New("fail") }, want: ErrInitialization},
+		{name: "session stopping", hook: func(h *Handle) error {
+			go h.stop(nil)
+			for !h.isStopping() {
+				time.Sleep(time.Millisecond)
+			}
+			return nil
+		}, want: errSessionNotRunning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, h := startDynamicTest(t)
+			defer func() { _ = h.Stop(context.Background()) }()
+			old := &dynamicSubscriberTest{}
+			if err := s.AddComponent(DefaultName, func() Component { return old }); err != nil {
+				t.Fatalf("AddComponent() error = %v", err)
+			}
+			eventType := QualifiedName(dynamicEventTest{})
+			subscribed := len(s.eventbus.subscriptions()[eventType])
+			if subscribed != 1 {
+				t.Fatalf("%d handlers subscribed, want 1", subscribed)
+			}
+			err := s.ReplaceComponent(dynamicSubscriberName, func() Component {
+				return &dynamicSubscriberTest{hook: func() error { return tt.hook(h) }}
+			})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ReplaceComponent() error = %v, want %v", err, tt.want)
+			}
+			if got := len(s.eventbus.subscriptions()[eventType]); got != subscribed {
+				t.Errorf("%d handlers subscribed, want %d", got, subscribed)
+			}
+			if cm := s.registry.item(dynamicSubscriberName); cm == nil || (cm.component != old) != (tt.want == nil) {
+				t.Errorf("only a failed replacement must keep the old component registered")
+			}
+		})
+	}
+}
@@ -38,6 +38,7 @@ type eventBus struct {
 	isStarted bool
 	queue     []any        // the queue which will receive the events until the init phase is  changed
 	queueLock sync.RWMutex // a mutex for the queue of events
+	parent    *eventBus    // receives the events of a child session, if they bubble up. It may be nil.
 }
 
 // Handler is a function which has one argument. This argument is usually a published event. An error
@@ -97,6 +98,10 @@ func (bus *eventBus) activate() error {
 		err := bus.Publish(event)
 		if err != nil {
 			Logger.Error.Printf("publishing queued event failed on eventbus start: %v", err.Error())
+			if isSessionEvent(event) {
+				// the session must not be interrupted by a subscriber of its lifecycle events
+				continue
+			}
 			if p, ok := err.(*PublishError); ok { //nolint:errorlint // casting required
 				pubErr.addPublishError(p)
 			} else {
@@ -194,6 +199,19 @@ func (bus *eventBus) HasHandler(handler Handler) bool {
 	return false
 }
 
+// subscriptions returns the qualified names of all subscribed handlers by the event type.
+func (bus *eventBus) subscriptions() map[string][]string {
+	bus.lock.RLock()
+	defer bus.lock.RUnlock()
+	subscriptions := make(map[string][]string)
+	for eventType, listeners := range bus.handlers {
+		for _, listener := range listeners {
+			subscriptions[eventType] = append(subscriptions[eventType], listener.qualifiedName)
+		}
+	}
+	return subscriptions
+}
+
 // Unsubscribe removes handler defined for a message type.
 // Returns error if there are no handlers subscribed to the message type.
 func (bus *eventBus) Unsubscribe(handler Handler) error {
@@ -215,6 +233,48 @@ func (bus *eventBus) Unsubscribe(handler Handler) error {
 	return fmt.Errorf("eventType %s doesn't exist", QualifiedName(handler))
 }
 
+// listenersAddedBy calls the function and returns the bus listeners, which were subscribed while it was running.
+// Without a bus, only the function is called.
+func (bus *eventBus) listenersAddedBy(f func() error) ([]*busListener, error) {
+	if bus == nil {
+		return nil, f()
+	}
+	before := make(map[*busListener]bool)
+	bus.lock.RLock()
+	for _, listeners := range bus.handlers {
+		for _, listener := range listeners {
+			before[listener] = true
+		}
+	}
+	bus.lock.RUnlock()
+	err := f()
+	var added []*busListener
+	bus.lock.RLock()
+	defer bus.lock.RUnlock()
+	for _, listeners := range bus.handlers {
+		for _, listener := range listeners {
+			if !before[listener] {
+				added = append(added, listener)
+			}
+		}
+	}
+	return added, err
+}
+
+// removeListeners unsubscribes the bus listeners.
+func (bus *eventBus) removeListeners(listeners []*busListener) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	for _, listener := range listeners {
+		for i, l := range bus.handlers[listener.eventTypeName] {
+			if l == listener {
+				bus.removeHandler(listener.eventTypeName, i)
+				break
+			}
+		}
+	}
+}
+
 // PublishError will be provided by the
 type PublishError struct {
 	failedListeners map[Event]map[*busListener]error
@@ -305,7 +365,13 @@ func (bus *eventBus) Publish(event Event) (err error) {
 		bus.lock.RUnlock()
 		pErr := bus.publish(event, copyHandlers)
 		if pErr != nil {
-			return pErr
+			err = pErr
+		}
+	}
+	if bus.parent != nil && !isSessionEvent(event) {
+		// the event bubbles up even if a local handler failed, but the local error has precedence
+		if parentErr := bus.parent.Publish(event); err == nil {
+			err = parentErr
 		}
 	}
 	return err
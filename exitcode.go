@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import "errors"
+
+// ExitCoder is an error, which provides the process exit code. It can be returned by a component to
+// choose the exit code used by Run.
+type ExitCoder interface {
+	error
+	// ExitCode returns the process exit code.
+	ExitCode() int
+}
+
+const (
+	// ExitCodeSuccess is used when the session was shut down without any error.
+	ExitCodeSuccess = 0
+	// ExitCodeFailure is used for all errors, which can't be assigned to another exit code.
+	ExitCodeFailure = 1
+	// ExitCodeConfiguration is used when a configuration value is missing or invalid.
+	ExitCodeConfiguration = 2
+	// ExitCodeInjection is used when the dependencies of a component couldn't be resolved.
+	ExitCodeInjection = 3
+	// ExitCodeInitialization is used when the initialization of a component failed.
+	ExitCodeInitialization = 4
+	// ExitCodeProcess is used when a process failed while starting or stopping.
+	ExitCodeProcess = 5
+	// ExitCodeShutdownTimeout is used when the components couldn't be stopped within the shutdown timeout.
+	ExitCodeShutdownTimeout = 6
+)
+
+var (
+	// ErrConfiguration is matched by all errors caused by a missing or invalid configuration value.
+	ErrConfiguration = errors.New("configuration failed")
+	// ErrInjection is matched by all errors caused by a failed dependency injection.
+	ErrInjection = errors.New("dependency injection failed")
+	// ErrInitialization is matched by all errors caused by a failed component initialization.
+	ErrInitialization = errors.New("initialization failed")
+	// ErrProcess is matched by all errors caused by a process, which failed while starting or stopping.
+	ErrProcess = errors.New("process failed")
+	// ErrShutdownTimeout is used when a component couldn't be stopped within the shutdown timeout.
+	ErrShutdownTimeout = errors.New("shutdown timeout exceeded")
+)
+
+// ExitCode returns the process exit code for the error returned by Go. An ExitCoder found in the
+// error chain has precedence over all predefined exit codes.
+func ExitCode(err error) int {
+	var coder ExitCoder
+	switch {
+	case err == nil:
+		return ExitCodeSuccess
+	case errors.As(err, &coder):
+		return coder.ExitCode()
+	case errors.Is(err, ErrShutdownTimeout):
+		return ExitCodeShutdownTimeout
+	case errors.Is(err, ErrConfiguration):
+		return ExitCodeConfiguration
+	case errors.Is(err, ErrInjection):
+		return ExitCodeInjection
+	case errors.Is(err, ErrInitialization):
+		return ExitCodeInitialization
+	case errors.Is(err, ErrProcess):
+		return ExitCodeProcess
+	}
+	return ExitCodeFailure
+}
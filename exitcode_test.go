@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type exitCodeTestError struct {
+	code int
+}
+
+func (e *exitCodeTestError) Error() string {
+	return "exit code test error"
+}
+
+func (e *exitCodeTestError) ExitCode() int {
+	return e.code
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "no error", err: nil, want: ExitCodeSuccess},
+		{name: "unknown error", err: errors.New("fail"), want: ExitCodeFailure},
+		{name: "exit coder", err: &exitCodeTestError{code: 42}, want: 42},
+		{name: "wrapped exit coder", err: fmt.Errorf("wrapped: %w", &exitCodeTestError{code: 43}), want: 43},
+		{name: "configuration", err: &DependencyInjectionError{error: errors.New("fail"), kind: ErrConfiguration}, want: ExitCodeConfiguration},
+		{name: "injection", err: &DependencyInjectionError{error: errors.New("fail")}, want: ExitCodeInjection},
+		{name: "initialization", err: &initializationError{errors.New("fail")}, want: ExitCodeInitialization},
+		{name: "process", err: &ComponentError{Err: errors.New("fail"), kind: ErrProcess}, want: ExitCodeProcess},
+		{name: "component", err: &ComponentError{Err: errors.New("fail")}, want: ExitCodeFailure},
+		{name: "shutdown timeout", err: &ComponentError{Err: ErrShutdownTimeout}, want: ExitCodeShutdownTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type exitCodeStartTest struct {
+	err error
+}
+
+func (c *exitCodeStartTest) Init() error { return nil }
+
+func (c *exitCodeStartTest) Start() error { return c.err }
+
+func (c *exitCodeStartTest) Stop() error { return nil }
+
+type exitCodeStopTest struct {
+	block chan struct{}
+}
+
+func (c *exitCodeStopTest) Init() error {
+	c.block = make(chan struct{})
+	return nil
+}
+
+func (c *exitCodeStopTest) Start() error {
+	<-c.block
+	return nil
+}
+
+func (c *exitCodeStopTest) Stop() error {
+	time.Sleep(2 * time.Second)
+	close(c.block)
+	return nil
+}
+
+type exitCodeEventTest struct{}
+
+// exitCodePublishTest publishes an event in Init, which fails when the eventbus is activated
+type exitCodePublishTest struct {
+	Eventbus EventBus `boot:"wire"`
+}
+
+func (c *exitCodePublishTest) Init() error {
+	_ = c.Eventbus.Subscribe(func(exitCodeEventTest) error { return errors.New("fail") })
+	return c.Eventbus.Publish(exitCodeEventTest{})
+}
+
+type exitCodeConfigTest struct {
+	Value int `boot:"config,key:EXIT_CODE_TEST_MISSING,panic"`
+}
+
+func (c *exitCodeConfigTest) Init() error { return nil }
+
+func TestSessionGoExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		create   func() Component
+		timeout  time.Duration
+		config   string
+		shutdown bool
+		want     int
+	}{
+		{name: "success", create: func() Component { return &bootTestComponent{} }, want: ExitCodeSuccess},
+		{name: "configuration", create: func() Component { return &exitCodeConfigTest{} }, want: ExitCodeConfiguration},
+		{name: "injection", create: func() Component { return &bootMissingDependencyComponent{} }, want: ExitCodeInjection},
+		{name: "initialization", create: func() Component { return &bootPanicComponent{content: "fail"} }, want: ExitCodeInitialization},
+		{name: "process", create: func() Component { return &exitCodeStartTest{err: errors.New("fail")} }, want: ExitCodeProcess},
+		{name: "eventbus activation", create: func() Component { return &exitCodePublishTest{} }, want: ExitCodeFailure},
+		{name: "process exit coder", create: func() Component { return &exitCodeStartTest{err: &exitCodeTestError{code: 42}} }, want: 42},
+		{name: "shutdown timeout", create: func() Component { return &exitCodeStopTest{} }, timeout: time.Second, shutdown: true, want: ExitCodeShutdownTimeout},
+		{name: "configured shutdown timeout", create: func() Component { return &exitCodeStopTest{} }, config: "1000", shutdown: true, want: ExitCodeShutdownTimeout},
+		{name: "invalid shutdown timeout", create: func() Component { return &bootTestComponent{} }, config: "soon", want: ExitCodeConfiguration},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.config != "" {
+				t.Setenv(shutdownTimeoutKey, tt.config)
+			}
+			s := newTestSession()
+			s.option.ShutdownTimeout = tt.timeout
+			if err := s.registerTestComponent(tt.create()); err != nil {
+				t.Fatal(err)
+			}
+			if tt.shutdown {
+				go func() {
+					time.Sleep(time.Second)
+					_ = s.Shutdown()
+				}()
+			}
+			if got := ExitCode(s.Go()); got != tt.want {
+				t.Errorf("ExitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type exitCodeStopDependentTest struct {
+	exitCodeStopTest
+	First *bootProcessesComponent `boot:"wire"`
+}
+
+func TestSessionShutdownTimeoutRemaining(t *testing.T) {
+	first := &bootProcessesComponent{}
+	blocking := &exitCodeStopDependentTest{}
+	s := newTestSession(first, blocking)
+	s.option.ShutdownTimeout = 200 * time.Millisecond
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	err = h.Stop(context.Background())
+	var sessionErr *SessionError
+	if !errors.As(err, &sessionErr) {
+		t.Fatalf("Stop() error = %v, want SessionError", err)
+	}
+	var names []string
+	for _, e := range sessionErr.Errors() {
+		if errors.Is(e, ErrShutdownTimeout) {
+			names = append(names, e.Name)
+		}
+	}
+	want := []string{
+		DefaultName + ":" + QualifiedName(blocking),
+		DefaultName + ":" + QualifiedName(first),
+	}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("timed out components = %v, want %v", names, want)
+	}
+	time.Sleep(2500 * time.Millisecond)
+	if state, _ := s.registry.item(DefaultName + ":" + QualifiedName(first)).getState(); state != Started {
+		t.Errorf("state = %v, want %v, because no component is stopped after the timeout", state, Started)
+	}
+}
+
+func TestRun(t *testing.T) {
+	defer func(e func(int)) { exit = e }(exit)
+	code := -1
+	exit = func(c int) {
+		code = c
+	}
+	globalSession = NewSession(UnitTestFlag)
+	Register(func() Component {
+		return &bootMissingDependencyComponent{}
+	})
+	Run()
+	if code != ExitCodeInjection {
+		t.Errorf("Run() exit code = %v, want %v", code, ExitCodeInjection)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FactoryContext provides read access to the configuration, the runtime flags and the registration name
+// while a component is created.
+type FactoryContext interface {
+	// Name returns the registration name of the component, e.g. default
+	Name() string
+	// Config returns the configuration value of the key. Named components look up the namespaced key
+	// <NAME>_<KEY> first.
+	Config(key string) (string, bool)
+	// HasFlag returns true, if the flag is set.
+	HasFlag(flag Flag) bool
+	// Flags returns all flags.
+	Flags() []Flag
+	// Bind returns the Binding of the created component, which declares dependencies and configuration values
+	// without struct tags. Only one component can be bound; binding another component fails the factory.
+	Bind(component Component) *Binding
+}
+
+// FactoryFunc creates a component with the FactoryContext. A returned error stops the boot.
+type FactoryFunc func(ctx FactoryContext) (Component, error)
+
+// FactoryError is returned, if a factory couldn't create a component.
+type FactoryError struct {
+	// Name is the registration name of the component, e.g. default
+	Name string
+	// Factory is the qualified name of the factory function
+	Factory string
+	// Err is the error returned by the factory
+	Err error
+}
+
+// Error is used to confirm to the error interface
+func (e *FactoryError) Error() string {
+	return fmt.Sprintf("factory %s for %s failed: %s", e.Factory, e.Name, e.Err.Error())
+}
+
+// Unwrap returns the error returned by the factory.
+func (e *FactoryError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrInitialization, because the component couldn't be created.
+func (e *FactoryError) Is(target error) bool {
+	return target == ErrInitialization //nolint:errorlint // sentinel comparison required
+}
+
+var (
+	errFactoryNilComponent    = errors.New("no component created")
+	errFactoryBindingMismatch = errors.New("binding doesn't belong to the created component")
+)
+
+// factoryContext is the FactoryContext of one registration
+type factoryContext struct {
+	name      string
+	configKey func(key string) string
+	lookup    func(key string) (string, bool)
+	flags     []Flag
+	binding   *Binding
+	// err is set, if the factory bound another component
+	err error
+}
+
+var _ FactoryContext = (*factoryContext)(nil)
+
+// Name is described in the FactoryContext interface
+func (ctx *factoryContext) Name() string {
+	return ctx.name
+}
+
+// Config is described in the FactoryContext interface
+func (ctx *factoryContext) Config(key string) (string, bool) {
+	_, value, ok := resolveConfig(configKeys(ctx.name, ctx.configKey, key, ""), ctx.lookup)
+	return value, ok
+}
+
+// HasFlag is described in the FactoryContext interface
+func (ctx *factoryContext) HasFlag(flag Flag) bool {
+	for _, f := range ctx.flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Flags is described in the FactoryContext interface
+func (ctx *factoryContext) Flags() []Flag {
+	return append([]Flag(nil), ctx.flags...)
+}
+
+// Bind is described in the FactoryContext interface
+func (ctx *factoryContext) Bind(component Component) *Binding {
+	if ctx.binding == nil {
+		ctx.binding = newBinding(component)
+	}
+	if ctx.binding.component != component {
+		// the fields of the other component are bound to a detached binding, which is never used
+		ctx.err = errFactoryBindingMismatch
+		return newBinding(component)
+	}
+	return ctx.binding
+}
+
+// RegisterFactory registers a factory function, which receives the FactoryContext and may return an error.
+// The component will be created on boot, if all conditions match.
+func (s *Session) RegisterFactory(build FactoryFunc, conditions ...Condition) error {
+	return s.RegisterNameFactory(DefaultName, build, conditions...)
+}
+
+// RegisterNameFactory registers a factory function with the given name, which receives the FactoryContext and
+// may return an error. The component will be created on boot, if all conditions match.
+func (s *Session) RegisterNameFactory(name string, build FactoryFunc, conditions ...Condition) error {
+	if name == "" || build == nil {
+		return errSessionRegisterNameOrFunction
+	}
+	return s.addFactory(factory{
+		build:      build,
+		name:       name,
+		conditions: conditions,
+	})
+}
+
+// qualifiedName returns the qualified name of the factory function
+func (f factory) qualifiedName() string {
+	if f.build != nil {
+		return QualifiedName(f.build)
+	}
+	return QualifiedName(f.create)
+}
+
+// newComponent calls the factory function
+func (f factory) newComponent(ctx FactoryContext) (Component, error) {
+	var component Component
+	if f.build != nil {
+		var err error
+		if component, err = f.build(ctx); err != nil {
+			return nil, &FactoryError{Name: f.name, Factory: f.qualifiedName(), Err: err}
+		}
+	} else {
+		component = f.create()
+	}
+	if component == nil {
+		return nil, &FactoryError{Name: f.name, Factory: f.qualifiedName(), Err: errFactoryNilComponent}
+	}
+	return component, nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"testing"
+)
+
+type factoryTest struct {
+	url string
+}
+
+func (c *factoryTest) Init() error { return nil }
+
+func TestSessionRegisterFactory(t *testing.T) {
+	t.Setenv("FACTORY_TEST_URL", "plain")
+	t.Setenv("PRIMARY_FACTORY_TEST_URL", "primary")
+	s := NewSession(UnitTestFlag)
+	var got FactoryContext
+	err := s.RegisterNameFactory("primary", func(ctx FactoryContext) (Component, error) {
+		got = ctx
+		url, _ := ctx.Config("FACTORY_TEST_URL")
+		return &factoryTest{url: url}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterNameFactory() error = %v", err)
+	}
+	reg, err := s.createComponents()
+	if err != nil {
+		t.Fatalf("createComponents() error = %v", err)
+	}
+	cm := reg.item("primary:github.com/boot-go/boot/factoryTest")
+	if cm == nil || cm.component.(*factoryTest).url != "primary" {
+		t.Fatalf("component not created with the namespaced configuration")
+	}
+	if got.Name() != "primary" || !got.HasFlag(UnitTestFlag) || got.HasFlag(StandardFlag) || len(got.Flags()) != 1 {
+		t.Errorf("unexpected factory context %+v", got)
+	}
+}
+
+func TestSessionRegisterFactoryErrors(t *testing.T) {
+	errFactory := errors.New("no connection")
+	tests := []struct {
+		name  string
+		build FactoryFunc
+		want  error
+	}{
+		{name: "error", build: func(FactoryContext) (Component, error) { return nil, errFactory }, want: errFactory},
+		{name: "nil component", build: func(FactoryContext) (Component, error) { return nil, nil }, want: errFactoryNilComponent},
+		{name: "configuration", build: func(FactoryContext) (Component, error) { return nil, ErrConfiguration }, want: ErrConfiguration},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSession()
+			if err := s.RegisterFactory(tt.build); err != nil {
+				t.Fatalf("RegisterFactory() error = %v", err)
+			}
+			_, err := s.createComponents()
+			var factoryErr *FactoryError
+			if !errors.As(err, &factoryErr) || factoryErr.Name != DefaultName {
+				t.Fatalf("createComponents() error = %v, want factory error", err)
+			}
+			if !errors.Is(err, tt.want) || !errors.Is(err, ErrInitialization) {
+				t.Errorf("createComponents() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRegisterFactoryValidation(t *testing.T) {
+	s := NewSession()
+	if err := s.RegisterFactory(nil); !errors.Is(err, errSessionRegisterNameOrFunction) {
+		t.Errorf("RegisterFactory() error = %v, want %v", err, errSessionRegisterNameOrFunction)
+	}
+	if err := s.RegisterNameFactory("", func(FactoryContext) (Component, error) { return &factoryTest{}, nil }); !errors.Is(err, errSessionRegisterNameOrFunction) {
+		t.Errorf("RegisterNameFactory() error = %v, want %v", err, errSessionRegisterNameOrFunction)
+	}
+}
@@ -24,6 +24,8 @@
 package boot
 
 import (
+	"context"
+	"os"
 	gort "runtime"
 	"time"
 )
@@ -68,31 +70,116 @@ const (
 // globalSession is the one and only global variable
 var globalSession *Session
 
+// exit terminates the process and is replaced in tests.
+var exit = os.Exit
+
 func init() {
 	globalSession = NewSession(StandardFlag)
 }
 
-// Register a default factory function.
-func Register(create func() Component) {
-	RegisterName(DefaultName, create)
+// Register a default factory function, which is used if all conditions match.
+func Register(create func() Component, conditions ...Condition) {
+	RegisterName(DefaultName, create, conditions...)
+}
+
+// RegisterName registers a factory function with the given name, which is used if all conditions match.
+func RegisterName(name string, create func() Component, conditions ...Condition) {
+	err := globalSession.RegisterName(name, create, conditions...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterAdmin registers the optional admin component, which exposes the internals of the global session over http.
+func RegisterAdmin() {
+	err := globalSession.RegisterAdmin()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterControl registers the optional control component, which allows to operate the global session over
+// a unix domain socket.
+func RegisterControl() {
+	err := globalSession.RegisterControl()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterSystemd registers the optional Systemd component for the global session.
+func RegisterSystemd() {
+	err := globalSession.RegisterSystemd()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterUpgrade registers the optional upgrade component for the global session, which replaces the
+// process on SIGUSR2 without dropping connections.
+func RegisterUpgrade() {
+	err := globalSession.RegisterUpgrade()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterFactory registers a factory function, which receives the FactoryContext and may return an error.
+func RegisterFactory(build FactoryFunc, conditions ...Condition) {
+	RegisterNameFactory(DefaultName, build, conditions...)
+}
+
+// RegisterNameFactory registers a factory function with the given name, which receives the FactoryContext and
+// may return an error.
+func RegisterNameFactory(name string, build FactoryFunc, conditions ...Condition) {
+	err := globalSession.RegisterNameFactory(name, build, conditions...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterInstances registers a factory function, which creates one named instance for every name in the
+// configuration value of the key, e.g. DB_INSTANCES=primary,replica.
+func RegisterInstances(key string, create func() Component, conditions ...Condition) {
+	err := globalSession.RegisterInstances(key, create, conditions...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Use installs the module with all nested modules in the global session.
+func Use(module Module) {
+	err := globalSession.Use(module)
+	if err != nil {
+		panic(err)
+	}
 }
 
-// RegisterName registers a factory function with the given name.
-func RegisterName(name string, create func() Component) {
-	err := globalSession.RegisterName(name, create)
+// ExcludeModule prevents, that the module and its nested modules are used by the global session.
+func ExcludeModule(name string) {
+	err := globalSession.ExcludeModule(name)
 	if err != nil {
 		panic(err)
 	}
 }
 
-// Override a default factory function.
-func Override(create func() Component) {
-	OverrideName(DefaultName, create)
+// Override a default factory function, if all conditions match.
+func Override(create func() Component, conditions ...Condition) {
+	OverrideName(DefaultName, create, conditions...)
 }
 
-// OverrideName overrides a factory function with the given name.
-func OverrideName(name string, create func() Component) {
-	err := globalSession.OverrideName(name, create)
+// OverrideName overrides a factory function with the given name, if all conditions match.
+func OverrideName(name string, create func() Component, conditions ...Condition) {
+	err := globalSession.OverrideName(name, create, conditions...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// HandleSignal binds the signal to the action. E.g. HandleSignal(syscall.SIGHUP, ReloadAction) will publish
+// a ReloadEvent whenever SIGHUP is received.
+func HandleSignal(sig os.Signal, action SignalAction) {
+	err := globalSession.HandleSignal(sig, action)
 	if err != nil {
 		panic(err)
 	}
@@ -100,14 +187,18 @@ func OverrideName(name string, create func() Component) {
 
 // Go the boot component framework. This starts the execution process.
 func Go() error {
+	return GoContext(context.Background())
+}
+
+// GoContext the boot component framework. This starts the execution process. When the context is done,
+// all components will be stopped gracefully.
+func GoContext(ctx context.Context) error {
 	startTime := time.Now()
 	s := new(gort.MemStats)
 	gort.ReadMemStats(s)
 	// output some basic info
-	const kilobyte = 1024
-	const megabyte = kilobyte * 2
 	Logger.Info.Printf("booting `boot-go %s` /// %s OS/%s ARCH/%s CPU/%d MEM/%dMB SYS/%dMB\n", version, gort.Version(), gort.GOOS, gort.GOARCH, gort.NumCPU(), s.Alloc/megabyte, s.Sys/megabyte)
-	err := globalSession.Go()
+	err := globalSession.GoContext(ctx)
 	if err == nil {
 		Logger.Info.Printf("exited after %s\n", time.Since(startTime).String())
 	} else {
@@ -116,6 +207,12 @@ func Go() error {
 	return err
 }
 
+// Run the boot component framework and exit the process afterwards. The exit code is determined by
+// ExitCode, so a component can choose the exit code by returning an ExitCoder error.
+func Run() {
+	exit(ExitCode(Go()))
+}
+
 // Shutdown boot-go componentManager. All components will be stopped. With standard options, this is equivalent with
 // issuing a SIGTERM on process level.
 func Shutdown() error {
@@ -24,6 +24,7 @@
 package boot
 
 import (
+	"context"
 	"errors"
 	"math"
 	"sync"
@@ -312,7 +313,9 @@ func TestResolveComponentError(t *testing.T) {
 	ts := newTestSession(testStruct)
 
 	err := ts.Go()
-	if err == nil || err.Error() != "Error dependency field is not a pointer receiver <bootMissingDependencyComponent.WireFails>" {
+	want := "validation failed for 1 component(s): default:github.com/boot-go/boot/bootMissingDependencyComponent: " +
+		"Error dependency field is not a pointer receiver <bootMissingDependencyComponent.WireFails>"
+	if err == nil || err.Error() != want {
 		t.Fatal("resolve dependency error must result in an exit with proper error message")
 	}
 }
@@ -605,3 +608,15 @@ func TestOverrideFail(t *testing.T) {
 		t.Fatal("boot failed")
 	}
 }
+
+func TestBootGoContext(t *testing.T) {
+	globalSession = NewSession(UnitTestFlag)
+	Register(func() Component {
+		return &bootProcessesComponent{}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := GoContext(ctx); err != nil {
+		t.Fatalf("GoContext() error = %v", err)
+	}
+}
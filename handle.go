@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Handle controls a session, which was started with Session.Start.
+type Handle struct {
+	session   *Session
+	instances componentManagers
+	done      chan struct{}
+	stopOnce  sync.Once
+	err       error
+	// mutex protects the instances and the stopping flag, because components can be added and removed
+	// while the session is running
+	mutex    sync.Mutex
+	stopping bool
+}
+
+func newHandle(s *Session, instances componentManagers) *Handle {
+	return &Handle{
+		session:   s,
+		instances: instances,
+		done:      make(chan struct{}),
+	}
+}
+
+// run blocks in DoMain until the shutdown is requested and stops all components afterwards.
+func (h *Handle) run() {
+	if h.session.option.DoMain == nil {
+		return
+	}
+	err := h.session.option.DoMain()
+	if err != nil {
+		Logger.Error.Printf("processing until shutdown failed with error: %v", err)
+	}
+	h.stop(err)
+}
+
+// stop will stop all components once. The provided error has precedence over all errors which occur
+// while stopping. Components aren't stopped on ErrImmediateShutdown.
+func (h *Handle) stop(err error) {
+	h.stopOnce.Do(func() {
+		s := h.session
+		h.mutex.Lock()
+		h.stopping = true
+		instances := append(componentManagers(nil), h.instances...)
+		h.mutex.Unlock()
+		if err := s.nextPhaseAfter(running); err != nil {
+			Logger.Error.Printf("component stop error: %v", err)
+		} else {
+			s.publishEvent(StoppingEvent{})
+		}
+		if errors.Is(err, ErrImmediateShutdown) {
+			Logger.Warn.Printf("%d components not stopped due to immediate shutdown", instances.count())
+		} else {
+			s.stopChildren()
+			s.stopComponents(instances)
+			Logger.Debug.Printf("%d components stopped", instances.count())
+		}
+		s.listeners.closeInherited()
+
+		if phaseErr := s.nextPhaseAfter(stopping); phaseErr != nil {
+			if err == nil {
+				err = phaseErr
+			}
+		} else {
+			s.publishEvent(ExitingEvent{})
+		}
+		if err == nil && s.errors.hasErrors() {
+			err = s.errors
+		}
+		Logger.Debug.Printf("boot done")
+		h.err = err
+		close(h.done)
+	})
+}
+
+// add starts the component and adds it to the instances, which are stopped on shutdown.
+func (h *Handle) add(cm *componentManager) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.stopping {
+		return errSessionNotRunning
+	}
+	h.instances = append(h.instances, cm)
+	cm.start()
+	return nil
+}
+
+// remove removes the component from the instances, which are stopped on shutdown.
+func (h *Handle) remove(cm *componentManager) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.stopping {
+		return errSessionNotRunning
+	}
+	for i, instance := range h.instances {
+		if instance == cm {
+			h.instances = append(h.instances[:i], h.instances[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// replace calls swap and puts the replacement at the position of the old component in the instances, before it
+// is started. Nothing is changed, if the session is stopping.
+func (h *Handle) replace(old, replacement *componentManager, swap func()) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.stopping {
+		return errSessionNotRunning
+	}
+	swap()
+	for i, instance := range h.instances {
+		if instance == old {
+			h.instances[i] = replacement
+			replacement.start()
+			return nil
+		}
+	}
+	h.instances = append(h.instances, replacement)
+	replacement.start()
+	return nil
+}
+
+// isStopping returns true, if the session is stopping.
+func (h *Handle) isStopping() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.stopping
+}
+
+// Wait blocks until all components are stopped and returns the same error as Err.
+func (h *Handle) Wait() error {
+	<-h.done
+	return h.err
+}
+
+// Stop requests the shutdown of the session and waits until all components are stopped or the context
+// is done. It returns the error of the session or the error of the context.
+func (h *Handle) Stop(ctx context.Context) error {
+	select {
+	case <-h.done:
+		return h.err
+	default:
+	}
+	if err := h.session.Shutdown(); err != nil {
+		return err
+	}
+	go h.stop(nil)
+	select {
+	case <-h.done:
+		return h.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done returns a channel, which is closed when all components are stopped.
+func (h *Handle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Err returns nil, as long as the session is running. Afterwards, it returns the error, which caused the
+// session to fail, or nil if the session was shut down cleanly. A *SessionError contains all errors, which
+// were returned by the components.
+func (h *Handle) Err() error {
+	select {
+	case <-h.done:
+		return h.err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSessionStart(t *testing.T) {
+	s := newTestSession(&bootProcessesComponent{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if s.phase != running {
+		t.Errorf("Start() returned in phase %s", s.phase)
+	}
+	select {
+	case <-h.Done():
+		t.Fatal("Done() must not be closed while running")
+	default:
+	}
+	if h.Err() != nil {
+		t.Errorf("Err() = %v, want nil while running", h.Err())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	<-h.Done()
+	if err := h.Wait(); err != nil {
+		t.Errorf("Wait() error = %v", err)
+	}
+	if s.phase != exiting {
+		t.Errorf("Wait() returned in phase %s", s.phase)
+	}
+}
+
+func TestSessionStartWithoutDoMain(t *testing.T) {
+	s := newTestSessionWithOptions(Options{}, &bootProcessesComponent{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := s.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	select {
+	case <-h.Done():
+		t.Fatal("session without DoMain must run until Handle.Stop is called")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() on stopped session error = %v", err)
+	}
+}
+
+func TestSessionStartCanceled(t *testing.T) {
+	s := newTestSession(&bootTestComponent{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Start(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Start() error = %v, want %v", err, context.Canceled)
+	}
+	if p := s.currentPhase(); p != exiting {
+		t.Errorf("Start() canceled in phase %s, want %s", p, exiting)
+	}
+}
+
+type handleCancelTest struct {
+	cancel context.CancelFunc
+}
+
+func (c *handleCancelTest) Init() error {
+	c.cancel()
+	return nil
+}
+
+func (c *handleCancelTest) Start() error { return nil }
+
+func (c *handleCancelTest) Stop() error { return nil }
+
+func TestSessionStartCanceledAfterInit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newTestSession(&handleCancelTest{cancel: cancel})
+	if _, err := s.Start(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Start() error = %v, want %v", err, context.Canceled)
+	}
+	if p := s.currentPhase(); p != exiting {
+		t.Errorf("Start() canceled in phase %s, want %s", p, exiting)
+	}
+	for _, info := range s.Components() {
+		if info.State != Stopped {
+			t.Errorf("%s state = %s, want %s", info.FullName(), info.State, Stopped)
+		}
+	}
+}
+
+type handleStopTest struct {
+	block chan struct{}
+}
+
+func (c *handleStopTest) Init() error {
+	c.block = make(chan struct{})
+	return nil
+}
+
+func (c *handleStopTest) Start() error {
+	<-c.block
+	return nil
+}
+
+func (c *handleStopTest) Stop() error {
+	time.Sleep(2 * time.Second)
+	close(c.block)
+	return nil
+}
+
+func TestHandleStopDeadline(t *testing.T) {
+	s := newTestSession(&handleStopTest{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := h.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := h.Wait(); err != nil {
+		t.Errorf("Wait() error = %v", err)
+	}
+}
+
+type componentsTest struct {
+	Eventbus EventBus `boot:"wire"`
+}
+
+func (c *componentsTest) Init() error {
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func TestSessionComponents(t *testing.T) {
+	s := newTestSession(&componentsTest{}, &bootProcessesComponent{})
+	if got := s.Components(); got != nil {
+		t.Fatalf("Components() = %v, want nil before start", got)
+	}
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	got := s.Components()
+	if len(got) != 6 {
+		t.Fatalf("Components() returned %d components, want 6", len(got))
+	}
+	infos := make(map[string]ComponentInfo)
+	for _, info := range got {
+		infos[info.FullName()] = info
+	}
+	cmp := infos["default:github.com/boot-go/boot/componentsTest"]
+	if cmp.Name != DefaultName || cmp.Type != "github.com/boot-go/boot/componentsTest" || cmp.State != Initialized ||
+		len(cmp.Dependencies) != 1 || cmp.Dependencies[0] != "default:github.com/boot-go/boot/eventBus" ||
+		cmp.InitDuration < 10*time.Millisecond || !cmp.StartTime.IsZero() {
+		t.Errorf("unexpected component info %+v", cmp)
+	}
+	process := infos["default:github.com/boot-go/boot/bootProcessesComponent"]
+	if process.State != Started || process.StartTime.IsZero() {
+		t.Errorf("unexpected process info %+v", process)
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	for _, info := range s.Components() {
+		if info.Type == "github.com/boot-go/boot/bootProcessesComponent" && info.State != Stopped {
+			t.Errorf("unexpected process state %s", info.State)
+		}
+	}
+}
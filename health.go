@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthStatus describes the health of a component or of the whole session.
+type HealthStatus int
+
+const (
+	// HealthUp is reported when the component works as expected.
+	HealthUp HealthStatus = iota
+	// HealthDegraded is reported when the component works with limitations, e.g. a cache is not available.
+	HealthDegraded
+	// HealthDown is reported when the component doesn't work.
+	HealthDown
+)
+
+// String returns the name of the health status
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthUp:
+		return "up"
+	case HealthDegraded:
+		return "degraded"
+	case HealthDown:
+		return "down"
+	}
+	return "unknown"
+}
+
+// MarshalText is used to encode the health status by its name, e.g. in JSON.
+func (s HealthStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// HealthChecker is implemented by components, which are able to report their health. The session
+// discovers all registered components implementing it.
+type HealthChecker interface {
+	// Check returns the health status and optional details. The check must return as soon as the
+	// context is done.
+	Check(ctx context.Context) (HealthStatus, map[string]any)
+}
+
+// HealthCheckResult contains the result of a single health check.
+type HealthCheckResult struct {
+	Status   HealthStatus   `json:"status"`
+	Details  map[string]any `json:"details,omitempty"`
+	Time     time.Time      `json:"time"`
+	Duration time.Duration  `json:"duration"`
+}
+
+// HealthReport contains the aggregated health status and the results of all checks by the full name of
+// the components.
+type HealthReport struct {
+	Status HealthStatus                 `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// Health is a standard component, which aggregates the health of all components.
+type Health interface {
+	// Liveness reports whether the session is alive. It is down, if any component has failed.
+	Liveness(ctx context.Context) HealthReport
+	// Readiness reports whether the session is ready to process requests. It runs all health checks
+	// concurrently and is down, unless the session is running.
+	Readiness(ctx context.Context) HealthReport
+}
+
+// health is the implementation of the standard Health component.
+type health struct {
+	// Timeout limits the duration of every health check in milliseconds.
+	Timeout int `boot:"config,key:BOOT_HEALTH_TIMEOUT,default:5000"`
+	// CacheDuration is the time in milliseconds a health check result will be reused.
+	CacheDuration int `boot:"config,key:BOOT_HEALTH_CACHE,default:1000"`
+	session       *Session
+	mutex         sync.Mutex
+	cache         map[string]HealthCheckResult
+}
+
+var _ Component = (*health)(nil) // Verify conformity to Component
+
+var _ Health = (*health)(nil) // Verify conformity to Health
+
+func newHealth(s *Session) *health {
+	return &health{
+		session: s,
+		cache:   make(map[string]HealthCheckResult),
+	}
+}
+
+// Init is described in the Component interface
+func (h *health) Init() error {
+	return nil
+}
+
+// Liveness is described in the Health interface
+func (h *health) Liveness(_ context.Context) HealthReport {
+	report := HealthReport{
+		Status: HealthUp,
+		Checks: make(map[string]HealthCheckResult),
+	}
+	for _, info := range h.session.Components() {
+		if info.State == Failed {
+			result := HealthCheckResult{
+				Status: HealthDown,
+				Time:   time.Now(),
+			}
+			if info.Err != nil {
+				result.Details = map[string]any{"error": info.Err.Error()}
+			}
+			report.Checks[info.FullName()] = result
+			report.Status = HealthDown
+		}
+	}
+	return report
+}
+
+// Readiness is described in the Health interface
+func (h *health) Readiness(ctx context.Context) HealthReport {
+	report := HealthReport{
+		Status: HealthUp,
+		Checks: make(map[string]HealthCheckResult),
+	}
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for name, checker := range h.checkers() {
+		name, checker := name, checker
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := h.check(ctx, name, checker)
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Checks[name] = result
+			if result.Status > report.Status {
+				report.Status = result.Status
+			}
+		}()
+	}
+	wg.Wait()
+	if p := h.session.currentPhase(); p != running {
+		report.Status = HealthDown
+	}
+	return report
+}
+
+// checkers returns all components implementing the HealthChecker by their full name.
+func (h *health) checkers() map[string]HealthChecker {
+	checkers := make(map[string]HealthChecker)
+	h.session.changeMutex.Lock()
+	reg := h.session.registry
+	h.session.changeMutex.Unlock()
+	if reg == nil {
+		return checkers
+	}
+	for _, entry := range reg.all() {
+		if checker, ok := entry.component.(HealthChecker); ok {
+			checkers[entry.getFullName()] = checker
+		}
+	}
+	return checkers
+}
+
+// check returns the cached result or runs the health check with a timeout.
+func (h *health) check(ctx context.Context, name string, checker HealthChecker) HealthCheckResult {
+	h.mutex.Lock()
+	cached, ok := h.cache[name]
+	h.mutex.Unlock()
+	if ok && time.Since(cached.Time) < time.Duration(h.CacheDuration)*time.Millisecond {
+		return cached
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(h.Timeout)*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	done := make(chan HealthCheckResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- HealthCheckResult{
+					Status:  HealthDown,
+					Details: map[string]any{"error": fmt.Sprintf("health check panicked: %v", r)},
+				}
+			}
+		}()
+		status, details := checker.Check(ctx)
+		done <- HealthCheckResult{
+			Status:  status,
+			Details: details,
+		}
+	}()
+	var result HealthCheckResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = HealthCheckResult{
+			Status:  HealthDown,
+			Details: map[string]any{"error": ctx.Err().Error()},
+		}
+	}
+	result.Time = start
+	result.Duration = time.Since(start)
+	h.mutex.Lock()
+	h.cache[name] = result
+	h.mutex.Unlock()
+	return result
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type healthCheckTest struct {
+	status HealthStatus
+	delay  time.Duration
+	panics bool
+	calls  int
+}
+
+func (c *healthCheckTest) Init() error { return nil }
+
+func (c *healthCheckTest) Check(ctx context.Context) (HealthStatus, map[string]any) {
+	c.calls++
+	if c.panics {
+		panic("check failed")
+	}
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+	}
+	return c.status, map[string]any{"calls": c.calls}
+}
+
+type healthConsumerTest struct {
+	Health Health `boot:"wire"`
+}
+
+func (c *healthConsumerTest) Init() error { return nil }
+
+func TestHealthReadiness(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker *healthCheckTest
+		want    HealthStatus
+	}{
+		{name: "up", checker: &healthCheckTest{status: HealthUp}, want: HealthUp},
+		{name: "degraded", checker: &healthCheckTest{status: HealthDegraded}, want: HealthDegraded},
+		{name: "down", checker: &healthCheckTest{status: HealthDown}, want: HealthDown},
+		{name: "timeout", checker: &healthCheckTest{status: HealthUp, delay: time.Second}, want: HealthDown},
+		{name: "panic", checker: &healthCheckTest{panics: true}, want: HealthDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer := &healthConsumerTest{}
+			s := newTestSession(consumer, tt.checker, &bootProcessesComponent{})
+			h, err := s.Start(context.Background())
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer func() { _ = h.Stop(context.Background()) }()
+			consumer.Health.(*health).Timeout = 100
+			report := consumer.Health.Readiness(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("Readiness() status = %s, want %s", report.Status, tt.want)
+			}
+			if _, ok := report.Checks["default:github.com/boot-go/boot/healthCheckTest"]; !ok || len(report.Checks) != 1 {
+				t.Errorf("Readiness() checks = %v", report.Checks)
+			}
+		})
+	}
+}
+
+func TestHealthReadinessCache(t *testing.T) {
+	checker := &healthCheckTest{status: HealthUp}
+	consumer := &healthConsumerTest{}
+	s := newTestSession(consumer, checker, &bootProcessesComponent{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = h.Stop(context.Background()) }()
+	_ = consumer.Health.Readiness(context.Background())
+	_ = consumer.Health.Readiness(context.Background())
+	if checker.calls != 1 {
+		t.Errorf("health check called %d times, want 1", checker.calls)
+	}
+}
+
+func TestHealthReadinessNotRunning(t *testing.T) {
+	s := newTestSession()
+	hlth := newHealth(s.Session)
+	if report := hlth.Readiness(context.Background()); report.Status != HealthDown {
+		t.Errorf("Readiness() status = %s, want %s", report.Status, HealthDown)
+	}
+}
+
+func TestHealthLiveness(t *testing.T) {
+	consumer := &healthConsumerTest{}
+	s := newTestSession(consumer, &lifecycleProcessTest{startErr: errors.New("fail")}, &bootProcessesComponent{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if report := consumer.Health.Liveness(context.Background()); report.Status != HealthUp {
+		t.Errorf("Liveness() status = %s, want %s", report.Status, HealthUp)
+	}
+	time.Sleep(200 * time.Millisecond)
+	report := consumer.Health.Liveness(context.Background())
+	if report.Status != HealthDown || report.Checks["default:github.com/boot-go/boot/lifecycleProcessTest"].Details["error"] != "fail" {
+		t.Errorf("Liveness() = %+v, want %s", report, HealthDown)
+	}
+	_ = h.Stop(context.Background())
+}
+
+func TestHealthStatusString(t *testing.T) {
+	tests := []struct {
+		status HealthStatus
+		want   string
+	}{
+		{status: HealthUp, want: "up"},
+		{status: HealthDegraded, want: "degraded"},
+		{status: HealthDown, want: "down"},
+		{status: -1, want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got, _ := tt.status.MarshalText(); string(got) != tt.want {
+				t.Errorf("MarshalText() = %s, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -30,12 +30,15 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // DependencyInjectionError contains a detail description for the cause of the injection failure
 type DependencyInjectionError struct {
 	error
 	detail string
+	// kind is either ErrInjection or ErrConfiguration. If not set, ErrInjection is used.
+	kind error
 }
 
 const (
@@ -46,6 +49,14 @@ const (
 	fieldTagWireKey     = "key"
 	fieldTagWirePanic   = "panic"
 	fieldTagWireDefault = "default"
+	// fieldTagConfigSecret marks a configuration value, which must never be shown
+	fieldTagConfigSecret = "secret"
+	// fieldTagConfigTemplate declares the namespaced key of named components, e.g. {NAME}_DB_{KEY}
+	fieldTagConfigTemplate = "template"
+	// configTemplateName is replaced by the registration name in the template
+	configTemplateName = "{NAME}"
+	// configTemplateKey is replaced by the key in the template
+	configTemplateKey = "{KEY}"
 )
 
 const (
@@ -57,62 +68,186 @@ func (e *DependencyInjectionError) Error() string {
 	return fmt.Sprintf("Error %s %s", e.error.Error(), e.detail)
 }
 
-func resolveDependency(regEntry *componentManager, reg *registry) (entries []*componentManager, err error) {
-	// exit if this component is already initialized
-	if regEntry.state != Created {
+// Is reports whether the error is caused by the configuration (ErrConfiguration) or the wiring (ErrInjection).
+func (e *DependencyInjectionError) Is(target error) bool {
+	kind := ErrInjection
+	if e.kind != nil {
+		kind = e.kind
+	}
+	return target == kind //nolint:errorlint // sentinel comparison required
+}
+
+// initializationError marks errors returned by a component, which failed to initialize.
+type initializationError struct {
+	error
+}
+
+// Unwrap returns the underlying error.
+func (e *initializationError) Unwrap() error {
+	return e.error
+}
+
+// Is reports whether the target is ErrInitialization.
+func (e *initializationError) Is(target error) bool {
+	return target == ErrInitialization //nolint:errorlint // sentinel comparison required
+}
+
+// injectionFailure marks the error of the component, which couldn't be injected. The error of a dependency is
+// passed on unchanged, so it is reported for the dependency.
+type injectionFailure struct {
+	name string
+	error
+}
+
+// Unwrap returns the underlying error.
+func (e *injectionFailure) Unwrap() error {
+	return e.error
+}
+
+// failInjection marks the error of the component, unless it was already marked by a dependency.
+func failInjection(regEntry *componentManager, err error) error {
+	var failure *injectionFailure
+	if errors.As(err, &failure) {
+		return err
+	}
+	return &injectionFailure{name: regEntry.getFullName(), error: err}
+}
+
+// resolveDependency injects the dependencies and configuration values into the component and all components,
+// which it depends on. Afterwards, these components are validated and initialized in the order of their
+// dependencies.
+func resolveDependency(regEntry *componentManager, reg *registry) ([]*componentManager, error) {
+	entries, err := injectDependencies(regEntry, reg)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateComponents(entries, newValidationError()); err != nil {
+		return nil, err
+	}
+	if err := initComponents(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// injectDependencies injects the dependencies and configuration values into the component and all components,
+// which it depends on. The returned components are ordered by their dependencies.
+func injectDependencies(regEntry *componentManager, reg *registry) (entries []*componentManager, err error) {
+	// exit if this component is already injected
+	if regEntry.isInjected() {
 		return entries, nil
 	}
+	regEntry.injected = true
+	regEntry.resolving = true
+	defer func() { regEntry.resolving = false }()
 	Logger.Debug.Printf("resolving dependencies for %s", regEntry.getFullName())
+	var firstConfigStart time.Time
+	var configDuration time.Duration
 	reflectedComponent := reflect.ValueOf(regEntry.component)
 	if reflectedComponent.Kind() == reflect.Ptr {
 		reflectedComponent = reflectedComponent.Elem()
 	}
+	injections, err := collectInjections(regEntry, reflectedComponent)
+	if err != nil {
+		return nil, failInjection(regEntry, err)
+	}
+	for _, inj := range injections {
+		switch inj.tag.name {
+		case fieldTagWire:
+			regEntryName := inj.tag.options[fieldTagName]
+			if regEntryName == "" {
+				regEntryName = DefaultName
+			}
+			if resolvedEntries, err := processWiring(reg, regEntry, reflectedComponent, inj.field, inj.fieldValue, regEntryName); err == nil {
+				entries = append(entries, resolvedEntries...)
+			} else {
+				return nil, failInjection(regEntry, err)
+			}
+		case fieldTagConfig:
+			configStart := time.Now()
+			if err := processConfiguration(regEntry, reflectedComponent, inj.field, inj.fieldValue, inj.tag); err != nil {
+				return nil, failInjection(regEntry, err)
+			}
+			if firstConfigStart.IsZero() {
+				firstConfigStart = configStart
+			}
+			configDuration += time.Since(configStart)
+		}
+	}
+	if !firstConfigStart.IsZero() {
+		regEntry.timeline.recordDuration(regEntry.getFullName(), ConfigStep, firstConfigStart, configDuration)
+	}
+	entries = append(entries, regEntry)
+	return entries, nil
+}
+
+// initComponents initializes the components in the given order and stops on the first error. The handlers,
+// which a component subscribed in Init, are recorded and unsubscribed again, if Init fails.
+func initComponents(entries []*componentManager) error {
+	for _, regEntry := range entries {
+		Logger.Debug.Printf("initializing %s\n", regEntry.getFullName())
+		initStart := time.Now()
+		listeners, err := regEntry.eventbus.listenersAddedBy(func() error {
+			return initComponent(regEntry)
+		})
+		regEntry.timeline.record(regEntry.getFullName(), InitStep, initStart)
+		regEntry.stateChangeMutex.Lock()
+		regEntry.initDuration = time.Since(initStart)
+		regEntry.listeners = listeners
+		regEntry.stateChangeMutex.Unlock()
+		if err != nil {
+			regEntry.unsubscribe()
+			initErr := &initializationError{err}
+			regEntry.setState(Failed, initErr)
+			return initErr
+		}
+		regEntry.setState(Initialized, nil)
+	}
+	return nil
+}
+
+// injection describes a field, which is wired or configured by a struct tag or a Binding
+type injection struct {
+	field      reflect.StructField
+	fieldValue reflect.Value
+	tag        *tag
+}
+
+// collectInjections returns the fields with struct tags followed by the bound fields of the component.
+func collectInjections(regEntry *componentManager, reflectedComponent reflect.Value) ([]injection, error) {
+	var injections []injection
 	for j := 0; j < reflectedComponent.Type().NumField(); j++ {
 		field := reflectedComponent.Type().Field(j)
-		fieldValue := reflectedComponent.Field(j)
-		if tag, ok := field.Tag.Lookup(fieldTag); ok {
-			parsedTag, ok := parseStructTag(tag)
-			if !ok {
-				return nil, &DependencyInjectionError{
-					error: errors.New("field contains unparsable tag"),
-					detail: " <" + reflectedComponent.Type().Name() + "." + field.Name +
-						" `" + tag + "`>",
-				}
+		tag, ok := field.Tag.Lookup(fieldTag)
+		if !ok {
+			continue
+		}
+		parsedTag, ok := parseStructTag(tag)
+		if !ok {
+			return nil, &DependencyInjectionError{
+				error: errors.New("field contains unparsable tag"),
+				detail: " <" + reflectedComponent.Type().Name() + "." + field.Name +
+					" `" + tag + "`>",
 			}
-			switch parsedTag.name {
-			case fieldTagWire:
-				regEntryName := parsedTag.options[fieldTagName]
-				if regEntryName == "" {
-					regEntryName = DefaultName
-				}
-				if resolvedEntries, err := processWiring(reg, reflectedComponent, field, fieldValue, regEntryName); err == nil {
-					entries = append(entries, resolvedEntries...)
-				} else {
-					return nil, err
-				}
-			case fieldTagConfig:
-				if err := processConfiguration(reflectedComponent, field, fieldValue, parsedTag); err != nil {
-					return nil, err
-				}
-			default:
-				return nil, &DependencyInjectionError{
-					error: errors.New("dependency field has unsupported tag"),
-					detail: " <" + reflectedComponent.Type().Name() + "." + field.Name +
-						" `" + tag + "`>",
-				}
+		}
+		if parsedTag.name != fieldTagWire && parsedTag.name != fieldTagConfig {
+			return nil, &DependencyInjectionError{
+				error: errors.New("dependency field has unsupported tag"),
+				detail: " <" + reflectedComponent.Type().Name() + "." + field.Name +
+					" `" + tag + "`>",
 			}
 		}
+		injections = append(injections, injection{
+			field:      field,
+			fieldValue: reflectedComponent.Field(j),
+			tag:        parsedTag,
+		})
 	}
-	// initialize component
-	Logger.Debug.Printf("initializing %s\n", regEntry.getFullName())
-	err = initComponent(regEntry)
+	bound, err := regEntry.binding.injections(reflectedComponent)
 	if err != nil {
-		regEntry.state = Failed
 		return nil, err
 	}
-	regEntry.state = Initialized
-	entries = append(entries, regEntry)
-	return entries, nil
+	return append(injections, bound...), nil
 }
 
 func initComponent(resolveEntry *componentManager) (err error) {
@@ -135,52 +270,70 @@ func initComponent(resolveEntry *componentManager) (err error) {
 	return
 }
 
-func processWiring(reg *registry, reflectedComponent reflect.Value, field reflect.StructField, fieldValue reflect.Value, regEntryName string) ([]*componentManager, error) {
-	if fieldValue.Kind() != reflect.Ptr && fieldValue.Kind() != reflect.Interface {
+func processWiring(reg *registry, regEntry *componentManager, reflectedComponent reflect.Value, field reflect.StructField, fieldValue reflect.Value, regEntryName string) ([]*componentManager, error) {
+	fieldType := field.Type
+	ref, isRef := referenceOf(fieldValue)
+	if isRef {
+		fieldType = ref.referencedType()
+	} else if fieldValue.Kind() != reflect.Ptr && fieldValue.Kind() != reflect.Interface {
 		return nil, &DependencyInjectionError{
 			error:  errors.New("dependency field is not a pointer receiver"),
 			detail: "<" + reflectedComponent.Type().Name() + "." + field.Name + ">",
 		}
 	}
-	var matchingValues []reflect.Value
+	var matchingEntries []*componentManager
+	reg.itemsMutex.RLock()
 	for _, list := range reg.items {
 		e := list[regEntryName]
-		if e != nil {
-			controlValue := reflect.ValueOf(e.component)
-			if controlValue.Type().AssignableTo(field.Type) {
-				if fieldValue.CanSet() {
-					matchingValues = append(matchingValues, controlValue)
-				} else {
-					return nil, &DependencyInjectionError{
-						error:  errors.New("dependency value cannot be set into"),
-						detail: "<" + reflectedComponent.Type().Name() + "." + field.Name + ">",
-					}
-				}
-			}
+		if e != nil && reflect.ValueOf(e.component).Type().AssignableTo(fieldType) {
+			matchingEntries = append(matchingEntries, e)
+		}
+	}
+	reg.itemsMutex.RUnlock()
+	if len(matchingEntries) > 0 && !isRef && !fieldValue.CanSet() {
+		return nil, &DependencyInjectionError{
+			error:  errors.New("dependency value cannot be set into"),
+			detail: "<" + reflectedComponent.Type().Name() + "." + field.Name + ">",
+		}
+	}
+	wire := func(component Component) {
+		if isRef {
+			ref.set(component)
+		} else {
+			fieldValue.Set(reflect.ValueOf(component))
 		}
 	}
-	switch len(matchingValues) {
+	switch len(matchingEntries) {
 	case 1:
-		typeName := matchingValues[0].Elem().Type().PkgPath() + "/" + matchingValues[0].Elem().Type().Name()
-		e := reg.items[typeName][regEntryName]
-		if e.state == Created {
-			entries, err := resolveDependency(e, reg)
+		e := matchingEntries[0]
+		regEntry.addDependency(e)
+		if e.resolving {
+			return nil, &DependencyInjectionError{
+				error:  errors.New("dependency cycle detected"),
+				detail: "<" + strings.Join(e.dependencyCycle(), " -> ") + ">",
+			}
+		}
+		if !e.isInjected() {
+			entries, err := injectDependencies(e, reg)
 			if err != nil {
 				return nil, err
 			}
-			fieldValue.Set(reflect.ValueOf(e.component))
+			wire(e.component)
 			return entries, nil
 		}
-		fieldValue.Set(reflect.ValueOf(e.component))
+		wire(e.component)
 	case 0:
+		if reg.parent != nil {
+			return processWiring(reg.parent, regEntry, reflectedComponent, field, fieldValue, regEntryName)
+		}
 		return nil, &DependencyInjectionError{
 			error:  errors.New("dependency value not found for"),
 			detail: "<" + regEntryName + ":" + reflectedComponent.Type().Name() + "." + field.Name + ">",
 		}
 	default:
 		detail := regEntryName + ":" + reflectedComponent.Type().Name() + "." + field.Name
-		for _, v := range matchingValues {
-			detail += "[" + QualifiedName(v) + "]"
+		for _, e := range matchingEntries {
+			detail += "[" + QualifiedName(reflect.ValueOf(e.component)) + "]"
 		}
 		return nil, &DependencyInjectionError{
 			error:  errors.New("multiple dependency values found for"),
@@ -190,7 +343,49 @@ func processWiring(reg *registry, reflectedComponent reflect.Value, field reflec
 	return []*componentManager{}, nil // this
 }
 
-func processConfiguration(reflectedComponent reflect.Value, field reflect.StructField, fieldValue reflect.Value, tag *tag) error {
+// wiredReferences returns all wired references of the component, which refer to the wired component. An error
+// is returned, if the replacement can't be assigned to one of them or if the component is live and refers to
+// the wired component by a field, which isn't a Ref and can't be re-pointed safely.
+func wiredReferences(cm *componentManager, wired Component, replacement Component) ([]reference, error) {
+	var refs []reference
+	reflectedComponent := reflect.ValueOf(cm.component)
+	if reflectedComponent.Kind() == reflect.Ptr {
+		reflectedComponent = reflectedComponent.Elem()
+	}
+	injections, err := collectInjections(cm, reflectedComponent)
+	if err != nil {
+		return nil, err
+	}
+	state, _ := cm.getState()
+	live := state == Initialized || state == Started
+	for _, inj := range injections {
+		if inj.tag.name != fieldTagWire {
+			continue
+		}
+		if ref, ok := referenceOf(inj.fieldValue); ok {
+			if ref.get() != wired {
+				continue
+			}
+			if !reflect.TypeOf(replacement).AssignableTo(ref.referencedType()) {
+				return nil, &DependencyInjectionError{
+					error:  errors.New("replacement cannot be assigned to"),
+					detail: "<" + reflectedComponent.Type().Name() + "." + inj.field.Name + ">",
+				}
+			}
+			refs = append(refs, ref)
+			continue
+		}
+		if live && !inj.fieldValue.IsNil() && inj.fieldValue.Interface() == wired {
+			return nil, &DependencyInjectionError{
+				error:  errors.New("replaced dependency must be wired as Ref into"),
+				detail: "<" + reflectedComponent.Type().Name() + "." + inj.field.Name + ">",
+			}
+		}
+	}
+	return refs, nil
+}
+
+func processConfiguration(regEntry *componentManager, reflectedComponent reflect.Value, field reflect.StructField, fieldValue reflect.Value, tag *tag) error {
 	panicOnFail := false
 	defaultCfg := ""
 	hasDefault := false
@@ -202,28 +397,32 @@ func processConfiguration(reflectedComponent reflect.Value, field reflect.Struct
 		hasDefault = true
 	}
 	if tag.hasOption(fieldTagWireKey) {
-		if cfgKey := tag.options[fieldTagWireKey]; len(cfgKey) > 0 {
-			if cfgValue, ok := getConfig(cfgKey); ok || hasDefault {
+		if tagKey := tag.options[fieldTagWireKey]; len(tagKey) > 0 {
+			cfgKey, cfgValue, ok := regEntry.resolveConfig(tagKey, tag.options[fieldTagConfigTemplate])
+			if ok || hasDefault {
 				if !ok && hasDefault {
 					cfgValue = defaultCfg
 				}
 				if fieldValue.CanSet() {
-					err := processConfigValue(reflectedComponent, field, fieldValue, cfgValue, cfgKey, panicOnFail)
+					secret := tag.hasOption(fieldTagConfigSecret) || isSecretKey(cfgKey)
+					err := processConfigValue(reflectedComponent, field, fieldValue, cfgValue, cfgKey, panicOnFail, secret)
 					if err != nil {
 						return err
 					}
+					regEntry.addConfigValue(newConfigValue(field, fieldValue, cfgKey, !ok, secret))
 				}
 			} else {
 				if panicOnFail {
 					return &DependencyInjectionError{
 						error:  errors.New(errorTextLoadConfiguration + cfgKey),
 						detail: "<" + reflectedComponent.Type().Name() + "." + field.Name + ">",
+						kind:   ErrConfiguration,
 					}
 				}
 				Logger.Warn.Printf("failed to parse configuration value %s for %s\n", cfgValue, "<"+reflectedComponent.Type().Name()+"."+field.Name+">")
 			}
 		} else {
-			return fmt.Errorf("unsupported tag value %s", cfgKey)
+			return fmt.Errorf("unsupported tag value %s", tagKey)
 		}
 	} else {
 		return &DependencyInjectionError{
@@ -234,19 +433,104 @@ func processConfiguration(reflectedComponent reflect.Value, field reflect.Struct
 	return nil
 }
 
-func processConfigValue(reflectedComponent reflect.Value, field reflect.StructField, fieldValue reflect.Value, cfgValue string, cfgKey string, panicOnFail bool) error {
-	processConfigString(field, fieldValue, cfgValue, cfgKey)
-	err := processConfigInt(field, reflectedComponent, fieldValue, cfgValue, panicOnFail, cfgKey)
+func processConfigValue(reflectedComponent reflect.Value, field reflect.StructField, fieldValue reflect.Value, cfgValue string, cfgKey string, panicOnFail bool, secret bool) error {
+	processConfigString(field, fieldValue, cfgValue, cfgKey, secret)
+	err := processConfigInt(field, reflectedComponent, fieldValue, cfgValue, panicOnFail, cfgKey, secret)
 	if err != nil {
 		return err
 	}
-	err = processConfigBool(field, reflectedComponent, fieldValue, cfgValue, panicOnFail, cfgKey)
+	err = processConfigBool(field, reflectedComponent, fieldValue, cfgValue, panicOnFail, cfgKey, secret)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// ConfigValue describes an injected configuration value.
+type ConfigValue struct {
+	// Component is the full name of the component
+	Component string `json:"component"`
+	// Field is the name of the struct field
+	Field string `json:"field"`
+	// Key is the configuration key
+	Key string `json:"key"`
+	// Value is the effective value of the field. Secrets are redacted.
+	Value string `json:"value"`
+	// Default is true, if the default value was used
+	Default bool `json:"default"`
+	// Secret is true, if the value is tagged as secret or the key indicates a secret, e.g. DB_PASSWORD
+	Secret bool `json:"secret"`
+}
+
+// redacted replaces the value of secrets
+const redacted = "******"
+
+// secretKeyParts are used to identify secrets by their configuration key
+var secretKeyParts = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "CREDENTIAL", "PRIVATE", "APIKEY", "API_KEY"}
+
+// isSecretKey returns true, if the configuration key indicates a secret
+func isSecretKey(cfgKey string) bool {
+	upperKey := strings.ToUpper(cfgKey)
+	for _, part := range secretKeyParts {
+		if strings.Contains(upperKey, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// printableConfigValue returns the value or a placeholder for secrets, so they don't appear in any log
+func printableConfigValue(value string, secret bool) string {
+	if secret {
+		return redacted
+	}
+	return value
+}
+
+func newConfigValue(field reflect.StructField, fieldValue reflect.Value, cfgKey string, isDefault bool, isSecret bool) ConfigValue {
+	value := printableConfigValue(fmt.Sprint(fieldValue.Interface()), isSecret)
+	return ConfigValue{
+		Field:   field.Name,
+		Key:     cfgKey,
+		Value:   value,
+		Default: isDefault,
+		Secret:  isSecret,
+	}
+}
+
+// configKeys returns the keys, which are looked up in this order for the configuration key of a component
+// with the registration name. Named components look up the namespaced key <NAME>_<KEY> or the key created by
+// the template first, e.g. {NAME}_DB_{KEY}. The template has precedence over the mapping of instances.
+func configKeys(name string, mapping func(key string) string, key string, template string) []string {
+	if name == DefaultName && mapping == nil {
+		return []string{key}
+	}
+	var namespaced string
+	switch {
+	case template != "":
+		namespaced = strings.NewReplacer(configTemplateName, configKeyName(name), configTemplateKey, key).Replace(template)
+	case mapping != nil:
+		namespaced = mapping(key)
+	default:
+		namespaced = configKeyName(name) + "_" + key
+	}
+	if namespaced == key {
+		return []string{key}
+	}
+	return []string{namespaced, key}
+}
+
+// resolveConfig returns the first key with a configuration value. If no value was found, the first key is
+// returned.
+func resolveConfig(keys []string, lookup func(key string) (string, bool)) (string, string, bool) {
+	for _, key := range keys {
+		if value, ok := lookup(key); ok {
+			return key, value, true
+		}
+	}
+	return keys[0], "", false
+}
+
 func getConfig(cfgKey string) (string, bool) {
 	key := ""
 	for _, arg := range os.Args {
@@ -263,7 +547,7 @@ func getConfig(cfgKey string) (string, bool) {
 	return os.LookupEnv(cfgKey)
 }
 
-func processConfigBool(field reflect.StructField, componentValue reflect.Value, fieldValue reflect.Value, cfgValue string, panicOnFail bool, cfg string) error {
+func processConfigBool(field reflect.StructField, componentValue reflect.Value, fieldValue reflect.Value, cfgValue string, panicOnFail bool, cfg string, secret bool) error {
 	if field.Type.Name() == "bool" {
 		if !fieldValue.Bool() {
 			boolValue, err := strconv.ParseBool(cfgValue)
@@ -272,18 +556,19 @@ func processConfigBool(field reflect.StructField, componentValue reflect.Value,
 					return &DependencyInjectionError{
 						error:  errors.New(errorTextLoadConfiguration + cfg),
 						detail: "<" + componentValue.Type().Name() + "." + field.Name + ">",
+						kind:   ErrConfiguration,
 					}
 				}
-				Logger.Warn.Printf("failed to parse configuration value %s as boolean: %s\n", cfgValue, err)
+				Logger.Warn.Printf("failed to parse configuration value %s as boolean: %s\n", printableConfigValue(cfgValue, secret), err)
 			}
 			fieldValue.SetBool(boolValue)
-			Logger.Debug.Printf("setting boolean configuration %s=%s\n", cfg, cfgValue)
+			Logger.Debug.Printf("setting boolean configuration %s=%s\n", cfg, printableConfigValue(cfgValue, secret))
 		}
 	}
 	return nil
 }
 
-func processConfigInt(field reflect.StructField, componentValue reflect.Value, fieldValue reflect.Value, cfgValue string, panicOnFail bool, cfg string) error {
+func processConfigInt(field reflect.StructField, componentValue reflect.Value, fieldValue reflect.Value, cfgValue string, panicOnFail bool, cfg string, secret bool) error {
 	if field.Type.Name() == "int" {
 		if fieldValue.Int() == 0 {
 			const bitSize = 64
@@ -294,22 +579,23 @@ func processConfigInt(field reflect.StructField, componentValue reflect.Value, f
 					return &DependencyInjectionError{
 						error:  errors.New(errorTextLoadConfiguration + cfg),
 						detail: "<" + componentValue.Type().Name() + "." + field.Name + ">",
+						kind:   ErrConfiguration,
 					}
 				}
-				Logger.Warn.Printf("failed to parse configuration value %s as integer: %s\n", cfgValue, err)
+				Logger.Warn.Printf("failed to parse configuration value %s as integer: %s\n", printableConfigValue(cfgValue, secret), err)
 			}
 			fieldValue.SetInt(intValue)
-			Logger.Debug.Printf("setting integer configuration %s=%s\n", cfg, cfgValue)
+			Logger.Debug.Printf("setting integer configuration %s=%s\n", cfg, printableConfigValue(cfgValue, secret))
 		}
 	}
 	return nil
 }
 
-func processConfigString(field reflect.StructField, fieldValue reflect.Value, cfgValue string, cfg string) {
+func processConfigString(field reflect.StructField, fieldValue reflect.Value, cfgValue string, cfg string, secret bool) {
 	if field.Type.Name() == "string" {
 		if fieldValue.String() == "" {
 			fieldValue.SetString(cfgValue)
-			Logger.Debug.Printf("setting string configuration %s=%s\n", cfg, cfgValue)
+			Logger.Debug.Printf("setting string configuration %s=%s\n", cfg, printableConfigValue(cfgValue, secret))
 		}
 	}
 }
@@ -26,6 +26,7 @@ package boot
 import (
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -294,6 +295,65 @@ func TestGetConfig(t *testing.T) {
 	}
 }
 
+func TestComponentManagerResolveConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmpName   string
+		template  string
+		configKey func(key string) string
+		env       map[string]string
+		wantKey   string
+		wantValue string
+		wantOk    bool
+	}{
+		{name: "default name", cmpName: DefaultName, env: map[string]string{"NS_URL": "plain"}, wantKey: "NS_URL", wantValue: "plain", wantOk: true},
+		{name: "namespaced", cmpName: "primary", env: map[string]string{"NS_URL": "plain", "PRIMARY_NS_URL": "primary"}, wantKey: "PRIMARY_NS_URL", wantValue: "primary", wantOk: true},
+		{name: "fallback", cmpName: "primary", env: map[string]string{"NS_URL": "plain"}, wantKey: "NS_URL", wantValue: "plain", wantOk: true},
+		{name: "missing", cmpName: "primary", wantKey: "PRIMARY_NS_URL"},
+		{name: "template", cmpName: "eu-west", template: "DB_{NAME}_{KEY}", env: map[string]string{"DB_EU_WEST_NS_URL": "eu"}, wantKey: "DB_EU_WEST_NS_URL", wantValue: "eu", wantOk: true},
+		{name: "instance", cmpName: "replica", configKey: instanceConfigKey("NS_INSTANCES", "replica"), env: map[string]string{"NS_REPLICA_URL": "replica"}, wantKey: "NS_REPLICA_URL", wantValue: "replica", wantOk: true},
+		{name: "instance fallback", cmpName: "replica", configKey: instanceConfigKey("NS_INSTANCES", "replica"), env: map[string]string{"NS_URL": "plain"}, wantKey: "NS_URL", wantValue: "plain", wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cm := newComponentManager(tt.cmpName, &envTestStruct1{}, &sync.WaitGroup{})
+			cm.configKey = tt.configKey
+			key, value, ok := cm.resolveConfig("NS_URL", tt.template)
+			if key != tt.wantKey || value != tt.wantValue || ok != tt.wantOk {
+				t.Errorf("resolveConfig() = %s, %s, %v, want %s, %s, %v", key, value, ok, tt.wantKey, tt.wantValue, tt.wantOk)
+			}
+		})
+	}
+}
+
+type namespacedConfigTest struct {
+	URL string `boot:"config,key:NS_TEST_URL,default:none"`
+}
+
+func (c *namespacedConfigTest) Init() error { return nil }
+
+func TestNamespacedConfiguration(t *testing.T) {
+	t.Setenv("NS_TEST_URL", "plain")
+	t.Setenv("PRIMARY_NS_TEST_URL", "primary")
+	s := NewSession()
+	primary, replica := &namespacedConfigTest{}, &namespacedConfigTest{}
+	_ = s.RegisterName("primary", func() Component { return primary })
+	_ = s.RegisterName("replica", func() Component { return replica })
+	reg, err := s.createComponents()
+	if err != nil {
+		t.Fatalf("createComponents() error = %v", err)
+	}
+	if _, err := reg.resolveComponentDependencies(); err != nil {
+		t.Fatalf("resolveComponentDependencies() error = %v", err)
+	}
+	if primary.URL != "primary" || replica.URL != "plain" {
+		t.Errorf("URL = %s and %s, want primary and plain", primary.URL, replica.URL)
+	}
+}
+
 func FuzzGetConfig(f *testing.F) {
 	f.Fuzz(func(t *testing.T, a string) {
 		getConfig(a)
@@ -24,7 +24,9 @@
 package boot
 
 import (
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -127,3 +129,53 @@ func TestTesterWithMultipleTestComponents(t *testing.T) {
 		t.Errorf("Test failed: %s", err.Error())
 	}
 }
+
+type testerCycleOne struct {
+	Two *testerCycleTwo `boot:"wire"`
+}
+
+func (t *testerCycleOne) Init() error { return nil }
+
+type testerCycleTwo struct {
+	One *testerCycleOne `boot:"wire"`
+}
+
+func (t *testerCycleTwo) Init() error { return nil }
+
+type testerCycleSelf struct {
+	Self *testerCycleSelf `boot:"wire"`
+}
+
+func (t *testerCycleSelf) Init() error { return nil }
+
+func TestDependencyCycle(t *testing.T) {
+	one := "default:github.com/boot-go/boot/testerCycleOne"
+	two := "default:github.com/boot-go/boot/testerCycleTwo"
+	self := "default:github.com/boot-go/boot/testerCycleSelf"
+	tests := []struct {
+		name       string
+		components []Component
+		want       []string
+	}{
+		{name: "two components", components: []Component{&testerCycleOne{}, &testerCycleTwo{}}, want: []string{
+			"<" + one + " -> " + two + " -> " + one + ">",
+			"<" + two + " -> " + one + " -> " + two + ">",
+		}},
+		{name: "self", components: []Component{&testerCycleSelf{}}, want: []string{"<" + self + " -> " + self + ">"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestSession(tt.components...).Go()
+			if !errors.Is(err, ErrInjection) {
+				t.Fatalf("Go() error = %v, want %v", err, ErrInjection)
+			}
+			found := false
+			for _, want := range tt.want {
+				found = found || strings.HasSuffix(err.Error(), "dependency cycle detected "+want)
+			}
+			if !found {
+				t.Errorf("Go() error = %v, want cycle %v", err, tt.want)
+			}
+		})
+	}
+}
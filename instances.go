@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// instancesSuffix is removed from the configuration key of the instances to get the prefix of the
+// configuration keys of all instances, e.g. DB_INSTANCES results in DB.
+const instancesSuffix = "_INSTANCES"
+
+var errSessionRegisterInstancesKey = errors.New("configuration key for instances is required")
+
+// RegisterInstances registers a factory function, which creates one named instance for every name in the
+// configuration value of the key, e.g. DB_INSTANCES=primary,replica. The instances are wired by their name.
+// The configuration keys of every instance get the name of the instance as prefix, e.g. the key URL or DB_URL
+// is resolved as DB_PRIMARY_URL for the instance primary, before the plain key is used.
+func (s *Session) RegisterInstances(key string, create func() Component, conditions ...Condition) error {
+	if key == "" {
+		return errSessionRegisterInstancesKey
+	}
+	if create == nil {
+		return errSessionRegisterNameOrFunction
+	}
+	return s.addFactory(factory{
+		create:     create,
+		instances:  key,
+		conditions: conditions,
+	})
+}
+
+// expandInstances returns all factories, whereby a factory for instances is replaced by one factory for
+// every configured instance.
+func (s *Session) expandInstances() []factory {
+	s.changeMutex.Lock()
+	factories := append([]factory(nil), s.factories...)
+	s.changeMutex.Unlock()
+	expanded := make([]factory, 0, len(factories))
+	for _, f := range factories {
+		if f.instances == "" {
+			expanded = append(expanded, f)
+			continue
+		}
+		value, _ := s.lookupConfig(f.instances)
+		names := instanceNames(value)
+		if len(names) == 0 {
+			Logger.Warn.Printf("no instances of %s configured in %s", f.qualifiedName(), f.instances)
+		}
+		for _, name := range names {
+			instance := f
+			instance.name = name
+			instance.configKey = instanceConfigKey(f.instances, name)
+			expanded = append(expanded, instance)
+		}
+	}
+	return expanded
+}
+
+// instanceNames returns the names of the comma separated list without blanks and duplicates.
+func instanceNames(value string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// instanceConfigKey returns a function, which adds the name of the instance to the configuration keys, e.g.
+// URL or DB_URL becomes DB_PRIMARY_URL for the instance primary of DB_INSTANCES.
+func instanceConfigKey(instancesKey, name string) func(key string) string {
+	prefix := strings.TrimSuffix(instancesKey, instancesSuffix)
+	instancePrefix := prefix + "_" + configKeyName(name) + "_"
+	return func(key string) string {
+		return instancePrefix + strings.TrimPrefix(key, prefix+"_")
+	}
+}
+
+// configKeyName converts the name to upper case and replaces all characters, which aren't letters or
+// digits, with an underscore, e.g. eu-west becomes EU_WEST.
+func configKeyName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type instanceTest struct {
+	URL  string `boot:"config,key:DB_URL,default:none"`
+	Pool int    `boot:"config,key:POOL,default:1"`
+}
+
+func (c *instanceTest) Init() error { return nil }
+
+type instanceConsumerTest struct {
+	Primary *instanceTest `boot:"wire,name:primary"`
+	Replica *instanceTest `boot:"wire,name:replica"`
+}
+
+func (c *instanceConsumerTest) Init() error { return nil }
+
+func TestSessionRegisterInstances(t *testing.T) {
+	t.Setenv("DB_INSTANCES", "primary, replica,,primary")
+	t.Setenv("DB_PRIMARY_URL", "postgres://primary")
+	t.Setenv("DB_REPLICA_URL", "postgres://replica")
+	t.Setenv("DB_REPLICA_POOL", "5")
+	s := newTestSession(&bootProcessesComponent{})
+	if err := s.RegisterInstances("DB_INSTANCES", func() Component { return &instanceTest{} }); err != nil {
+		t.Fatalf("RegisterInstances() error = %v", err)
+	}
+	consumer := &instanceConsumerTest{}
+	if err := s.Register(func() Component { return consumer }); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = h.Stop(context.Background()) }()
+	if consumer.Primary.URL != "postgres://primary" || consumer.Primary.Pool != 1 {
+		t.Errorf("primary = %+v", consumer.Primary)
+	}
+	if consumer.Replica.URL != "postgres://replica" || consumer.Replica.Pool != 5 {
+		t.Errorf("replica = %+v", consumer.Replica)
+	}
+}
+
+func TestSessionRegisterInstancesErrors(t *testing.T) {
+	s := NewSession()
+	if err := s.RegisterInstances("", func() Component { return &instanceTest{} }); !errors.Is(err, errSessionRegisterInstancesKey) {
+		t.Errorf("RegisterInstances() error = %v, want %v", err, errSessionRegisterInstancesKey)
+	}
+	if err := s.RegisterInstances("DB_INSTANCES", nil); !errors.Is(err, errSessionRegisterNameOrFunction) {
+		t.Errorf("RegisterInstances() error = %v, want %v", err, errSessionRegisterNameOrFunction)
+	}
+}
+
+func TestInstanceConfigKey(t *testing.T) {
+	tests := []struct {
+		instances string
+		name      string
+		key       string
+		want      string
+	}{
+		{instances: "DB_INSTANCES", name: "primary", key: "URL", want: "DB_PRIMARY_URL"},
+		{instances: "DB_INSTANCES", name: "primary", key: "DB_URL", want: "DB_PRIMARY_URL"},
+		{instances: "DB_INSTANCES", name: "eu-west.1", key: "URL", want: "DB_EU_WEST_1_URL"},
+		{instances: "DATABASES", name: "replica", key: "URL", want: "DATABASES_REPLICA_URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := instanceConfigKey(tt.instances, tt.name)(tt.key); got != tt.want {
+				t.Errorf("instanceConfigKey() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
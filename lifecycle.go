@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+// BootingEvent is published when the session starts to create and initialize the components.
+type BootingEvent struct{}
+
+// RunningEvent is published when all components are initialized and started.
+type RunningEvent struct{}
+
+// StoppingEvent is published when the session starts to stop all components.
+type StoppingEvent struct{}
+
+// ExitingEvent is published when all components were stopped.
+type ExitingEvent struct{}
+
+// ComponentEvent is published whenever the state of a component has changed, i.e. it was Initialized,
+// Started, Stopped or Failed.
+type ComponentEvent struct {
+	// Name is the full name of the component, e.g. default:github.com/boot-go/boot/eventBus
+	Name string
+	// State is the new state of the component.
+	State ComponentState
+	// Err contains the error, if the component failed.
+	Err error
+}
+
+// publishEvent publishes the event on the session eventbus. Until the eventbus is activated, the events
+// are queued. An error is only logged, because the session must not be interrupted by a subscriber.
+func (s *Session) publishEvent(event Event) {
+	if err := s.eventbus.Publish(event); err != nil {
+		Logger.Error.Printf("publishing %s failed: %v", QualifiedName(event), err)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lifecycleEventTest struct {
+	Eventbus EventBus `boot:"wire"`
+	mutex    sync.Mutex
+	events   []string
+}
+
+func (c *lifecycleEventTest) Init() error {
+	record := func(e string) {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		c.events = append(c.events, e)
+	}
+	_ = c.Eventbus.Subscribe(func(e BootingEvent) { record("booting") })
+	_ = c.Eventbus.Subscribe(func(e RunningEvent) { record("running") })
+	_ = c.Eventbus.Subscribe(func(e StoppingEvent) { record("stopping") })
+	_ = c.Eventbus.Subscribe(func(e ExitingEvent) { record("exiting") })
+	_ = c.Eventbus.Subscribe(func(e ComponentEvent) {
+		if e.Name == "default:github.com/boot-go/boot/lifecycleProcessTest" {
+			if e.Err != nil {
+				record(e.State.String() + ":" + e.Err.Error())
+			} else {
+				record(e.State.String())
+			}
+		}
+	})
+	return nil
+}
+
+func (c *lifecycleEventTest) received() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]string(nil), c.events...)
+}
+
+type lifecycleProcessTest struct {
+	startErr error
+	block    chan struct{}
+}
+
+func (c *lifecycleProcessTest) Init() error {
+	c.block = make(chan struct{})
+	return nil
+}
+
+func (c *lifecycleProcessTest) Start() error {
+	if c.startErr != nil {
+		time.Sleep(100 * time.Millisecond)
+		return c.startErr
+	}
+	<-c.block
+	return nil
+}
+
+func (c *lifecycleProcessTest) Stop() error {
+	close(c.block)
+	return nil
+}
+
+func TestSessionLifecycleEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		process  *lifecycleProcessTest
+		shutdown bool
+		want     []string
+	}{
+		{
+			name:     "stopped",
+			process:  &lifecycleProcessTest{},
+			shutdown: true,
+			want:     []string{"booting", "initialized", "started", "running", "stopping", "stopped", "exiting"},
+		},
+		{
+			name:    "failed",
+			process: &lifecycleProcessTest{startErr: errors.New("fail")},
+			want:    []string{"booting", "initialized", "started", "running", "failed:fail", "stopping", "exiting"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := &lifecycleEventTest{}
+			s := newTestSession(listener, tt.process)
+			if tt.shutdown {
+				go func() {
+					time.Sleep(time.Second)
+					_ = s.Shutdown()
+				}()
+			}
+			_ = s.Go()
+			got := listener.received()
+			if len(got) != len(tt.want) {
+				t.Fatalf("received events %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("received events %v, want %v", got, tt.want)
+					return
+				}
+			}
+		})
+	}
+}
+
+type lifecycleFailingSubscriberTest struct {
+	Eventbus EventBus `boot:"wire"`
+}
+
+func (c *lifecycleFailingSubscriberTest) Init() error {
+	return c.Eventbus.Subscribe(func(e ComponentEvent) error { return errors.New("fail") })
+}
+
+func TestSessionLifecycleEventsFailingSubscriber(t *testing.T) {
+	s := newTestSession(&lifecycleFailingSubscriberTest{}, &lifecycleProcessTest{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	select {
+	case <-h.Done():
+		t.Fatalf("a failing subscriber must not end the session")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v, want nil", err)
+	}
+}
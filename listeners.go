@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// upgradeListenersEnv contains the names of the listeners passed to an upgraded process
+const upgradeListenersEnv = "BOOT_UPGRADE_LISTENERS"
+
+// upgradeFdsStart is the first file descriptor passed to an upgraded process
+var upgradeFdsStart = 3
+
+// Listeners is a standard component, which manages the listeners of the session. Listeners created by
+// the registry are passed to the new process on a zero-downtime upgrade, so no connection is dropped.
+type Listeners interface {
+	// Listen returns the listener inherited from the previous process with the given name. If there is
+	// none, a new listener is created like net.Listen. Every name can only be used once.
+	Listen(name, network, address string) (net.Listener, error)
+}
+
+// listenerRegistry is the implementation of the Listeners component.
+type listenerRegistry struct {
+	mutex     sync.Mutex
+	inherited map[string]net.Listener
+	listeners map[string]net.Listener
+	names     []string
+}
+
+var _ Component = (*listenerRegistry)(nil) // Verify conformity to Component
+
+var _ Listeners = (*listenerRegistry)(nil) // Verify conformity to Listeners
+
+// errListenerNameInUse is returned when a listener name is used twice
+var errListenerNameInUse = errors.New("listener name already in use")
+
+func newListenerRegistry() *listenerRegistry {
+	return &listenerRegistry{
+		inherited: make(map[string]net.Listener),
+		listeners: make(map[string]net.Listener),
+	}
+}
+
+// Init is described in the Component interface. The listeners passed by the previous process are taken over.
+func (r *listenerRegistry) Init() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	value, ok := os.LookupEnv(upgradeListenersEnv)
+	if !ok {
+		return nil
+	}
+	_ = os.Unsetenv(upgradeListenersEnv)
+	for i, name := range strings.Split(value, ":") {
+		if name == "" {
+			continue
+		}
+		file := os.NewFile(uintptr(upgradeFdsStart+i), name)
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("inheriting listener %s failed: %w", name, err)
+		}
+		Logger.Debug.Printf("inherited listener %s on %s", name, listener.Addr())
+		r.inherited[name] = listener
+	}
+	return nil
+}
+
+// Listen is described in the Listeners interface
+func (r *listenerRegistry) Listen(name, network, address string) (net.Listener, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.listeners[name]; ok {
+		return nil, fmt.Errorf("%w: %s", errListenerNameInUse, name)
+	}
+	listener, ok := r.inherited[name]
+	if ok {
+		delete(r.inherited, name)
+	} else {
+		var err error
+		listener, err = net.Listen(network, address)
+		if err != nil {
+			return nil, err
+		}
+	}
+	r.listeners[name] = listener
+	r.names = append(r.names, name)
+	return listener, nil
+}
+
+// all returns all listeners with their names in the order of creation
+func (r *listenerRegistry) all() ([]string, []net.Listener) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	listeners := make([]net.Listener, 0, len(r.names))
+	for _, name := range r.names {
+		listeners = append(listeners, r.listeners[name])
+	}
+	return append([]string(nil), r.names...), listeners
+}
+
+// closeInherited closes all inherited listeners, which weren't requested.
+func (r *listenerRegistry) closeInherited() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for name, listener := range r.inherited {
+		Logger.Warn.Printf("closing unused inherited listener %s", name)
+		_ = listener.Close()
+		delete(r.inherited, name)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestListenerRegistryListen(t *testing.T) {
+	r := newListenerRegistry()
+	if err := r.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		network string
+		address string
+		wantErr error
+	}{
+		{name: "http", network: "tcp", address: "localhost:0"},
+		{name: "metrics", network: "tcp", address: "localhost:0"},
+		{name: "http", network: "tcp", address: "localhost:0", wantErr: errListenerNameInUse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := r.Listen(tt.name, tt.network, tt.address)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Listen() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				t.Cleanup(func() {
+					_ = l.Close()
+				})
+			}
+		})
+	}
+	names, listeners := r.all()
+	if len(names) != 2 || names[0] != "http" || names[1] != "metrics" || len(listeners) != 2 {
+		t.Errorf("all() = %v, want http and metrics", names)
+	}
+}
+
+func TestListenerRegistryInherited(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	r := newListenerRegistry()
+	r.inherited["http"] = l
+	got, err := r.Listen("http", "tcp", "localhost:0")
+	if err != nil || got != l {
+		t.Errorf("Listen() = %v, %v, want the inherited listener", got, err)
+	}
+	r.inherited["unused"] = l
+	r.closeInherited()
+	if len(r.inherited) != 0 {
+		t.Errorf("closeInherited() kept %d listeners", len(r.inherited))
+	}
+}
@@ -0,0 +1,305 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Module bundles registrations, nested modules and default configuration values as a reusable unit, e.g. a
+// boot stack. A module is installed with Use and can be excluded by its name with ExcludeModule.
+type Module struct {
+	// Name identifies the module, e.g. github.com/boot-go/stack/web
+	Name string
+	// Version of the module, e.g. v1.2.0
+	Version string
+	// Registrations contains the factories of the module
+	Registrations []Registration
+	// Modules contains nested modules, which are installed with this module
+	Modules []Module
+	// Config contains default configuration values, which are used if the key isn't provided as argument or
+	// environment variable. The values of a module have precedence over the values of its nested modules.
+	Config map[string]string
+	// Conditions apply to all registrations of the module and its nested modules
+	Conditions []Condition
+}
+
+// Registration describes a factory of a module.
+type Registration struct {
+	// Name is used for the registration. DefaultName is used, if it is empty.
+	Name string
+	// Create is the factory function
+	Create func() Component
+	// Override replaces an existing registration with the same name
+	Override bool
+	// Conditions must match, otherwise the registration is skipped
+	Conditions []Condition
+}
+
+// ModuleInfo describes an installed module.
+type ModuleInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Parents are the names of the modules, which contain this module. It is empty, if the module was only
+	// installed directly with Use.
+	Parents []string `json:"parents,omitempty"`
+	// Registrations is the number of registrations of the module without its nested modules
+	Registrations int `json:"registrations"`
+	// Excluded is true, if the module or all of its parents were excluded
+	Excluded bool `json:"excluded"`
+}
+
+// moduleEntry is an installed module
+type moduleEntry struct {
+	info   ModuleInfo
+	config map[string]string
+	// direct is true, if the module was installed with Use and not only as nested module
+	direct bool
+}
+
+// moduleCollector collects the entries and the factories of a module with all nested modules. A module,
+// which is already installed or collected with the same version, is only collected once.
+type moduleCollector struct {
+	installed []*moduleEntry
+	entries   []*moduleEntry
+	factories []factory
+	// collecting contains the names of the modules, whose nested modules are collected
+	collecting map[string]bool
+	// parents contains the parents, which are added to the installed modules
+	parents map[*moduleEntry][]string
+}
+
+var (
+	errModuleName             = errors.New("module name is required")
+	errModuleAlreadyInstalled = errors.New("module already installed")
+)
+
+// Use installs the module with all nested modules. A module, which is used several times with the same
+// version, e.g. by two modules, is installed only once. It must be called before the session is started.
+func (s *Session) Use(module Module) error {
+	defer s.changeMutex.Unlock()
+	s.changeMutex.Lock()
+	if s.phase != initializing {
+		return errSessionRegisterComponentOutsideInitialize
+	}
+	collector := &moduleCollector{
+		installed:  s.modules,
+		collecting: make(map[string]bool),
+		parents:    make(map[*moduleEntry][]string),
+	}
+	if err := collector.collect(module, "", nil); err != nil {
+		return err
+	}
+	for entry, parents := range collector.parents {
+		for _, parent := range parents {
+			entry.addParent(parent)
+		}
+	}
+	s.modules = append(s.modules, collector.entries...)
+	s.factories = append(s.factories, collector.factories...)
+	return nil
+}
+
+// ExcludeModule prevents, that the registrations and the configuration of the module and its nested
+// modules are used. It must be called before the session is started.
+func (s *Session) ExcludeModule(name string) error {
+	if name == "" {
+		return errModuleName
+	}
+	defer s.changeMutex.Unlock()
+	s.changeMutex.Lock()
+	if s.phase != initializing {
+		return errSessionRegisterComponentOutsideInitialize
+	}
+	s.excludedModules[name] = true
+	return nil
+}
+
+// Modules returns all installed modules in the order their configuration is applied.
+func (s *Session) Modules() []ModuleInfo {
+	defer s.changeMutex.Unlock()
+	s.changeMutex.Lock()
+	infos := make([]ModuleInfo, 0, len(s.modules))
+	for _, entry := range s.modules {
+		info := entry.info
+		info.Parents = append([]string(nil), info.Parents...)
+		info.Excluded = s.isModuleExcluded(info.Name)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// collect adds the entries and the factories of the module. The nested modules are collected before the
+// module itself, so the configuration of the module takes precedence. A module with the name of an installed
+// or collected module is skipped, if the versions are equal, otherwise an error is returned.
+func (c *moduleCollector) collect(module Module, parent string, conditions []Condition) error {
+	if module.Name == "" {
+		return errModuleName
+	}
+	if c.collecting[module.Name] {
+		return fmt.Errorf("%w: %s contains itself", errModuleAlreadyInstalled, module.Name)
+	}
+	if entry, installed := c.find(module.Name); entry != nil {
+		if entry.info.Version != module.Version {
+			return fmt.Errorf("%w: %s %s conflicts with version %s", errModuleAlreadyInstalled, module.Name, module.Version, entry.info.Version)
+		}
+		if installed {
+			c.parents[entry] = append(c.parents[entry], parent)
+		} else {
+			entry.addParent(parent)
+		}
+		return nil
+	}
+	c.collecting[module.Name] = true
+	defer delete(c.collecting, module.Name)
+	conditions = append(append([]Condition{}, conditions...), module.Conditions...)
+	for _, nested := range module.Modules {
+		if err := c.collect(nested, module.Name, conditions); err != nil {
+			return err
+		}
+	}
+	for _, registration := range module.Registrations {
+		name := registration.Name
+		if name == "" {
+			name = DefaultName
+		}
+		if registration.Create == nil {
+			return fmt.Errorf("%w in module %s", errSessionRegisterNameOrFunction, module.Name)
+		}
+		c.factories = append(c.factories, factory{
+			create:     registration.Create,
+			name:       name,
+			override:   registration.Override,
+			conditions: append(append([]Condition{}, conditions...), registration.Conditions...),
+			module:     module.Name,
+		})
+	}
+	config := make(map[string]string, len(module.Config))
+	for key, value := range module.Config {
+		config[key] = value
+	}
+	entry := &moduleEntry{
+		info: ModuleInfo{
+			Name:          module.Name,
+			Version:       module.Version,
+			Registrations: len(module.Registrations),
+		},
+		config: config,
+	}
+	entry.addParent(parent)
+	c.entries = append(c.entries, entry)
+	return nil
+}
+
+// find returns the collected or installed module with the name and whether it was installed before.
+func (c *moduleCollector) find(name string) (*moduleEntry, bool) {
+	for _, entry := range c.entries {
+		if entry.info.Name == name {
+			return entry, false
+		}
+	}
+	for _, entry := range c.installed {
+		if entry.info.Name == name {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// addParent adds the name of a module, which contains this module. An empty name marks the module as
+// installed directly.
+func (e *moduleEntry) addParent(parent string) {
+	if parent == "" {
+		e.direct = true
+		return
+	}
+	for _, p := range e.info.Parents {
+		if p == parent {
+			return
+		}
+	}
+	e.info.Parents = append(e.info.Parents, parent)
+}
+
+// module returns the installed module with the name or nil. The changeMutex must be locked.
+func (s *Session) module(name string) *moduleEntry {
+	for _, entry := range s.modules {
+		if entry.info.Name == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// isModuleExcluded returns true, if the module is excluded or if all modules containing it are excluded. A
+// module installed directly is only excluded by its name. The changeMutex must be locked.
+func (s *Session) isModuleExcluded(name string) bool {
+	if s.excludedModules[name] {
+		return true
+	}
+	entry := s.module(name)
+	if entry == nil || entry.direct || len(entry.info.Parents) == 0 {
+		return false
+	}
+	for _, parent := range entry.info.Parents {
+		if !s.isModuleExcluded(parent) {
+			return false
+		}
+	}
+	return true
+}
+
+// prepareModules merges the configuration of all modules, which aren't excluded, and returns the names of
+// the excluded modules.
+func (s *Session) prepareModules() map[string]bool {
+	defer s.changeMutex.Unlock()
+	s.changeMutex.Lock()
+	excluded := make(map[string]bool)
+	s.moduleConfig = make(map[string]string)
+	for _, entry := range s.modules {
+		if s.isModuleExcluded(entry.info.Name) {
+			excluded[entry.info.Name] = true
+			Logger.Info.Printf("module %s %s excluded", entry.info.Name, entry.info.Version)
+			continue
+		}
+		Logger.Info.Printf("using module %s %s", entry.info.Name, entry.info.Version)
+		for key, value := range entry.config {
+			s.moduleConfig[key] = value
+		}
+	}
+	return excluded
+}
+
+// lookupConfig returns the configuration value from the arguments, the environment variables or the
+// configuration of the modules.
+func (s *Session) lookupConfig(key string) (string, bool) {
+	if value, ok := getConfig(key); ok {
+		return value, ok
+	}
+	defer s.changeMutex.Unlock()
+	s.changeMutex.Lock()
+	value, ok := s.moduleConfig[key]
+	return value, ok
+}
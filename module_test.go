@@ -0,0 +1,250 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type moduleTest struct {
+	Port string `boot:"config,key:MODULE_TEST_PORT,default:80"`
+}
+
+func (c *moduleTest) Init() error { return nil }
+
+type moduleNestedTest struct{}
+
+func (c *moduleNestedTest) Init() error { return nil }
+
+func newModuleTest(config map[string]string, conditions ...Condition) Module {
+	return Module{
+		Name:          "module",
+		Version:       "v1.0.0",
+		Registrations: []Registration{{Create: func() Component { return &moduleTest{} }}},
+		Modules: []Module{{
+			Name:          "nested",
+			Registrations: []Registration{{Create: func() Component { return &moduleNestedTest{} }}},
+			Config:        map[string]string{"MODULE_TEST_PORT": "8080"},
+		}},
+		Config:     config,
+		Conditions: conditions,
+	}
+}
+
+func TestSessionUse(t *testing.T) {
+	tests := []struct {
+		name       string
+		module     Module
+		exclude    string
+		env        map[string]string
+		wantModule bool
+		wantNested bool
+		wantPort   string
+	}{
+		{name: "nested config", module: newModuleTest(nil), wantModule: true, wantNested: true, wantPort: "8080"},
+		{name: "module config", module: newModuleTest(map[string]string{"MODULE_TEST_PORT": "9090"}), wantModule: true, wantNested: true, wantPort: "9090"},
+		{name: "environment", module: newModuleTest(map[string]string{"MODULE_TEST_PORT": "9090"}), env: map[string]string{"MODULE_TEST_PORT": "7070"}, wantModule: true, wantNested: true, wantPort: "7070"},
+		{name: "exclude nested", module: newModuleTest(nil), exclude: "nested", wantModule: true, wantPort: "80"},
+		{name: "exclude module", module: newModuleTest(nil), exclude: "module"},
+		{name: "condition fails", module: newModuleTest(nil, OnFlag(UnitTestFlag))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			s := NewSession()
+			if err := s.Use(tt.module); err != nil {
+				t.Fatalf("Use() error = %v", err)
+			}
+			if tt.exclude != "" {
+				if err := s.ExcludeModule(tt.exclude); err != nil {
+					t.Fatalf("ExcludeModule() error = %v", err)
+				}
+			}
+			reg, err := s.createComponents()
+			if err != nil {
+				t.Fatalf("createComponents() error = %v", err)
+			}
+			if got := reg.has(conditionType((*moduleTest)(nil))); got != tt.wantModule {
+				t.Errorf("module component registered = %v, want %v", got, tt.wantModule)
+			}
+			if got := reg.has(conditionType((*moduleNestedTest)(nil))); got != tt.wantNested {
+				t.Errorf("nested component registered = %v, want %v", got, tt.wantNested)
+			}
+			if !tt.wantModule {
+				return
+			}
+			if _, err := reg.resolveComponentDependencies(); err != nil {
+				t.Fatalf("resolveComponentDependencies() error = %v", err)
+			}
+			for _, cm := range reg.items[QualifiedName(&moduleTest{})] {
+				if got := cm.component.(*moduleTest).Port; got != tt.wantPort {
+					t.Errorf("Port = %s, want %s", got, tt.wantPort)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionUseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		module Module
+		want   error
+	}{
+		{name: "missing name", module: Module{}, want: errModuleName},
+		{name: "missing nested name", module: Module{Name: "other", Modules: []Module{{}}}, want: errModuleName},
+		{name: "missing function", module: Module{Name: "other", Registrations: []Registration{{}}}, want: errSessionRegisterNameOrFunction},
+		{name: "other version", module: Module{Name: "module", Version: "v2.0.0"}, want: errModuleAlreadyInstalled},
+		{name: "other nested version", module: Module{Name: "other", Modules: []Module{{Name: "module", Version: "v2.0.0"}}}, want: errModuleAlreadyInstalled},
+		{name: "other version in tree", module: Module{Name: "other", Modules: []Module{{Name: "shared"}, {Name: "sub", Modules: []Module{{Name: "shared", Version: "v2.0.0"}}}}}, want: errModuleAlreadyInstalled},
+		{name: "contains itself", module: Module{Name: "other", Modules: []Module{{Name: "other"}}}, want: errModuleAlreadyInstalled},
+		{name: "failed after shared", module: Module{Name: "other", Modules: []Module{{Name: "module"}, {}}}, want: errModuleName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSession()
+			if err := s.Use(Module{Name: "module"}); err != nil {
+				t.Fatalf("Use() error = %v", err)
+			}
+			if err := s.Use(tt.module); !errors.Is(err, tt.want) {
+				t.Errorf("Use() error = %v, want %v", err, tt.want)
+			}
+			if got := s.Modules(); len(got) != 1 || len(got[0].Parents) != 0 {
+				t.Errorf("Modules() = %+v, want the module installed before", got)
+			}
+		})
+	}
+}
+
+func TestSessionUseAfterStart(t *testing.T) {
+	s := newTestSession(&bootProcessesComponent{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = h.Stop(context.Background()) }()
+	if err := s.Use(newModuleTest(nil)); !errors.Is(err, errSessionRegisterComponentOutsideInitialize) {
+		t.Errorf("Use() error = %v, want %v", err, errSessionRegisterComponentOutsideInitialize)
+	}
+	if err := s.ExcludeModule("module"); !errors.Is(err, errSessionRegisterComponentOutsideInitialize) {
+		t.Errorf("ExcludeModule() error = %v, want %v", err, errSessionRegisterComponentOutsideInitialize)
+	}
+}
+
+func TestSessionModules(t *testing.T) {
+	s := NewSession()
+	if err := s.Use(newModuleTest(nil)); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+	if err := s.ExcludeModule("module"); err != nil {
+		t.Fatalf("ExcludeModule() error = %v", err)
+	}
+	want := []ModuleInfo{
+		{Name: "nested", Parents: []string{"module"}, Registrations: 1, Excluded: true},
+		{Name: "module", Version: "v1.0.0", Registrations: 1, Excluded: true},
+	}
+	got := s.Modules()
+	if len(got) != len(want) {
+		t.Fatalf("Modules() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("Modules()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSessionUseShared(t *testing.T) {
+	shared := Module{
+		Name:          "shared",
+		Version:       "v1.0.0",
+		Registrations: []Registration{{Create: func() Component { return &moduleNestedTest{} }}},
+	}
+	tests := []struct {
+		name       string
+		exclude    []string
+		wantShared bool
+	}{
+		{name: "diamond", wantShared: true},
+		{name: "one parent excluded", exclude: []string{"left"}, wantShared: true},
+		{name: "all parents excluded", exclude: []string{"left", "right"}},
+		{name: "shared excluded", exclude: []string{"shared"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSession()
+			if err := s.Use(Module{Name: "left", Modules: []Module{shared}}); err != nil {
+				t.Fatalf("Use() error = %v", err)
+			}
+			if err := s.Use(Module{Name: "right", Modules: []Module{shared}}); err != nil {
+				t.Fatalf("Use() error = %v", err)
+			}
+			for _, name := range tt.exclude {
+				if err := s.ExcludeModule(name); err != nil {
+					t.Fatalf("ExcludeModule() error = %v", err)
+				}
+			}
+			modules := s.Modules()
+			if len(modules) != 3 || !reflect.DeepEqual(modules[0].Parents, []string{"left", "right"}) {
+				t.Errorf("Modules() = %+v, want shared once with both parents", modules)
+			}
+			reg, err := s.createComponents()
+			if err != nil {
+				t.Fatalf("createComponents() error = %v", err)
+			}
+			if got := len(reg.items[QualifiedName(&moduleNestedTest{})]); (got > 0) != tt.wantShared {
+				t.Errorf("shared components = %d, want registered %v", got, tt.wantShared)
+			}
+		})
+	}
+}
+
+func TestSessionUseSharedInTree(t *testing.T) {
+	shared := Module{Name: "shared", Registrations: []Registration{{Create: func() Component { return &moduleNestedTest{} }}}}
+	s := NewSession()
+	if err := s.Use(Module{Name: "root", Modules: []Module{shared, {Name: "sub", Modules: []Module{shared}}}}); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+	if err := s.Use(shared); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+	if got := len(s.Modules()); got != 3 {
+		t.Errorf("Modules() returned %d modules, want 3", got)
+	}
+	if err := s.ExcludeModule("root"); err != nil {
+		t.Fatalf("ExcludeModule() error = %v", err)
+	}
+	reg, err := s.createComponents()
+	if err != nil {
+		t.Fatalf("createComponents() error = %v", err)
+	}
+	if got := len(reg.items[QualifiedName(&moduleNestedTest{})]); got != 1 {
+		t.Errorf("shared components = %d, want 1, because it was installed directly", got)
+	}
+}
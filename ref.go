@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Ref is a wired reference to a component, which can be replaced with ReplaceComponent while the session is
+// running. A field of type Ref[T] is wired like a field of type T, e.g.
+//
+//	type server struct {
+//		Store boot.Ref[Store] `boot:"wire"`
+//	}
+//
+// The referenced component is returned by Get. A replacement is visible to all following calls of Get.
+type Ref[T any] struct {
+	mutex sync.RWMutex
+	value T
+}
+
+// reference is implemented by Ref to wire and re-point it without knowing the type of the component.
+type reference interface {
+	// referencedType returns the type of the referenced component
+	referencedType() reflect.Type
+	// get returns the referenced component
+	get() any
+	// set changes the referenced component
+	set(value any)
+}
+
+var _ reference = (*Ref[any])(nil) // Verify conformity to reference
+
+// Get returns the referenced component.
+func (r *Ref[T]) Get() T {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.value
+}
+
+func (r *Ref[T]) referencedType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (r *Ref[T]) get() any {
+	return r.Get()
+}
+
+func (r *Ref[T]) set(value any) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.value = value.(T) //nolint:forcetypeassert // the type was checked while wiring
+}
+
+// referenceOf returns the reference, if the field is a Ref.
+func referenceOf(fieldValue reflect.Value) (reference, bool) {
+	if fieldValue.Kind() != reflect.Struct || !fieldValue.CanAddr() || !fieldValue.Addr().CanInterface() {
+		return nil, false
+	}
+	ref, ok := fieldValue.Addr().Interface().(reference)
+	return ref, ok
+}
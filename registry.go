@@ -25,6 +25,8 @@ package boot
 
 import (
 	"errors"
+	"reflect"
+	"sort"
 	"sync"
 )
 
@@ -33,8 +35,20 @@ type registry struct {
 	// items are organized in hierarchy, using the component name, componentManager name and containing
 	// the componentManager.
 	items map[string]map[string]*componentManager
+	// itemsMutex protects the items, because components can be added and removed while the session is running
+	itemsMutex sync.RWMutex
 	// executionWaitGroup tracks the amount off active components
 	executionWaitGroup sync.WaitGroup
+	// changed is passed to every componentManager and called when the state of a component has changed.
+	changed func(cm *componentManager, state ComponentState, err error)
+	// timeline is passed to every componentManager to record the lifecycle steps. It may be nil.
+	timeline *timeline
+	// lookup is passed to every componentManager to resolve configuration values. It may be nil.
+	lookup func(key string) (string, bool)
+	// eventbus is passed to every componentManager to record the handlers subscribed in Init. It may be nil.
+	eventbus *eventBus
+	// parent is the registry of the parent session, which is used to wire missing dependencies. It may be nil.
+	parent *registry
 }
 
 // newRegistry creates a new component registry.
@@ -47,8 +61,10 @@ func newRegistry() *registry {
 
 // addItem adds a component componentManager to the registry.
 func (reg *registry) addItem(name string, override bool, cmp Component) error {
-	cmpMngr := newComponentManager(name, cmp, &reg.executionWaitGroup)
+	cmpMngr := reg.newItem(name, cmp)
 	id := cmpMngr.getName()
+	defer reg.itemsMutex.Unlock()
+	reg.itemsMutex.Lock()
 	if reg.items[id] == nil {
 		// enter first componentManager in registry
 		v := make(map[string]*componentManager)
@@ -74,16 +90,131 @@ func (reg *registry) addItem(name string, override bool, cmp Component) error {
 	return nil
 }
 
-func (reg *registry) resolveComponentDependencies() (componentManagers, error) {
+// newItem creates a componentManager for the component without adding it to the registry.
+func (reg *registry) newItem(name string, cmp Component) *componentManager {
+	cmpMngr := newComponentManager(name, cmp, &reg.executionWaitGroup)
+	cmpMngr.changed = reg.changed
+	cmpMngr.timeline = reg.timeline
+	cmpMngr.lookup = reg.lookup
+	cmpMngr.eventbus = reg.eventbus
+	return cmpMngr
+}
+
+// replaceItem replaces the componentManager in the registry.
+func (reg *registry) replaceItem(old, replacement *componentManager) {
+	defer reg.itemsMutex.Unlock()
+	reg.itemsMutex.Lock()
+	reg.deleteItem(old)
+	id := replacement.getName()
+	if reg.items[id] == nil {
+		reg.items[id] = make(map[string]*componentManager)
+	}
+	reg.items[id][replacement.name] = replacement
+}
+
+// item returns the componentManager with the full name or nil.
+func (reg *registry) item(fullName string) *componentManager {
+	for _, entry := range reg.all() {
+		if entry.getFullName() == fullName {
+			return entry
+		}
+	}
+	return nil
+}
+
+// removeItem removes the componentManager from the registry.
+func (reg *registry) removeItem(cm *componentManager) {
+	defer reg.itemsMutex.Unlock()
+	reg.itemsMutex.Lock()
+	reg.deleteItem(cm)
+}
+
+// deleteItem removes the componentManager from the registry. The itemsMutex must be locked.
+func (reg *registry) deleteItem(cm *componentManager) {
+	id := cm.getName()
+	if reg.items[id][cm.name] == cm {
+		delete(reg.items[id], cm.name)
+		if len(reg.items[id]) == 0 {
+			delete(reg.items, id)
+		}
+	}
+}
+
+// all returns a snapshot of all componentManagers.
+func (reg *registry) all() []*componentManager {
+	defer reg.itemsMutex.RUnlock()
+	reg.itemsMutex.RLock()
 	var entries []*componentManager
 	for _, cmpTypList := range reg.items {
 		for _, entry := range cmpTypList {
-			newEntries, err := resolveDependency(entry, reg)
-			if err != nil {
-				return nil, err
-			}
-			entries = append(entries, newEntries...)
+			entries = append(entries, entry)
 		}
 	}
+	return entries
+}
+
+func (reg *registry) resolveComponentDependencies() (componentManagers, error) {
+	var entries []*componentManager
+	validationErr := newValidationError()
+	for _, entry := range reg.all() {
+		newEntries, err := injectDependencies(entry, reg)
+		var failure *injectionFailure
+		if errors.As(err, &failure) {
+			// the remaining components are injected and validated, so all problems are reported at once
+			validationErr.Errors[failure.name] = failure.error
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, newEntries...)
+	}
+	// all components are validated before any component is initialized
+	if err := validateComponents(entries, validationErr); err != nil {
+		return nil, err
+	}
+	if err := initComponents(entries); err != nil {
+		return nil, err
+	}
 	return entries, nil
 }
+
+// has returns true, if a component of the type or implementing the interface type is registered.
+func (reg *registry) has(t reflect.Type) bool {
+	for _, entry := range reg.all() {
+		ct := reflect.TypeOf(entry.component)
+		if ct == t || (t != nil && t.Kind() == reflect.Interface && ct.Implements(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// components returns a snapshot of all components sorted by their full name.
+func (reg *registry) components() []ComponentInfo {
+	var infos []ComponentInfo
+	for _, entry := range reg.all() {
+		infos = append(infos, entry.info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].FullName() < infos[j].FullName()
+	})
+	return infos
+}
+
+// configuration returns all injected configuration values sorted by the component and the key.
+func (reg *registry) configuration() []ConfigValue {
+	var values []ConfigValue
+	for _, entry := range reg.all() {
+		entry.stateChangeMutex.Lock()
+		values = append(values, entry.config...)
+		entry.stateChangeMutex.Unlock()
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Component == values[j].Component {
+			return values[i].Key < values[j].Key
+		}
+		return values[i].Component < values[j].Component
+	})
+	return values
+}
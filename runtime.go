@@ -23,10 +23,52 @@
 
 package boot
 
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
 // runtime contains the configuration settings, which must be available globally for all components
 // at the same time. Do not use this for component configurations.
 type runtime struct {
-	modes []Flag
+	// CgroupRoot is the mount point of the cgroup file system, which contains the container limits.
+	CgroupRoot string `boot:"config,key:BOOT_CGROUP_ROOT,default:/sys/fs/cgroup"`
+	// Tuning sets GOMAXPROCS and the memory limit of the garbage collector to the container limits, unless
+	// it is disabled. The Go runtime is tuned only once per process.
+	Tuning bool `boot:"config,key:BOOT_RUNTIME_TUNING,default:true"`
+	// MemoryLimitPercent is the percentage of the container memory, which is used as the soft memory limit.
+	MemoryLimitPercent int `boot:"config,key:BOOT_MEMORY_LIMIT_PERCENT,default:90"`
+	// ConfiguredFlags contains additional comma separated flags, e.g. functionalTest,canary
+	ConfiguredFlags string `boot:"config,key:BOOT_FLAGS,default:''"`
+	// InstanceID identifies the running instance. A random id is used, if it isn't configured.
+	InstanceID string `boot:"config,key:BOOT_INSTANCE_ID,default:''"`
+	mutex      sync.RWMutex
+	modes      []Flag
+	limits     ResourceLimits
+	info       RuntimeInfo
+}
+
+// RuntimeInfo contains facts about the application and the environment it is running in.
+type RuntimeInfo struct {
+	// Name is the module path of the main package or the name of the executable
+	Name string `json:"name"`
+	// Version is the module version of the main package, e.g. (devel) when built locally
+	Version string `json:"version"`
+	// Revision is the VCS revision the executable was built from
+	Revision string `json:"revision,omitempty"`
+	// InstanceID identifies the running instance
+	InstanceID string `json:"instanceId"`
+	// Hostname is the host name reported by the kernel
+	Hostname string `json:"hostname"`
+	// StartTime is the time the session was booted
+	StartTime time.Time `json:"startTime"`
 }
 
 // Flag describes a special behaviour of a component
@@ -35,6 +77,12 @@ type Flag string
 // Runtime is a standard component, which is used to alternate the component behaviour at runtime.
 type Runtime interface {
 	HasFlag(flag Flag) bool
+	// Flags returns all flags
+	Flags() []Flag
+	// Info returns facts about the application and the environment
+	Info() RuntimeInfo
+	// ResourceLimits returns the CPU and memory limits of the container
+	ResourceLimits() ResourceLimits
 }
 
 const (
@@ -49,10 +97,34 @@ const (
 var _ Component = (*runtime)(nil) // Verify conformity to Component
 
 func (r *runtime) Init() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, flag := range parseFlags(r.ConfiguredFlags) {
+		if !r.hasFlag(flag) {
+			r.modes = append(r.modes, flag)
+		}
+	}
+	r.info = newRuntimeInfo(r.InstanceID)
+	r.limits = readResourceLimits(r.CgroupRoot)
+	if r.Tuning {
+		r.limits = r.limits.tuneOnce(r.MemoryLimitPercent)
+	}
+	Logger.Info.Printf("runtime limits /// CGROUP/v%d CPU_LIMIT/%s MEM_LIMIT/%s GOMAXPROCS/%d GOMEMLIMIT/%s\n",
+		r.limits.CgroupVersion,
+		formatLimit(r.limits.CPU > 0, strconv.FormatFloat(r.limits.CPU, 'f', -1, 64)),
+		formatLimit(r.limits.Memory > 0, strconv.FormatInt(r.limits.Memory/megabyte, 10)+"MB"),
+		r.limits.GoMaxProcs,
+		formatLimit(r.limits.MemoryLimit > 0, strconv.FormatInt(r.limits.MemoryLimit/megabyte, 10)+"MB"))
 	return nil
 }
 
 func (r *runtime) HasFlag(mode Flag) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.hasFlag(mode)
+}
+
+func (r *runtime) hasFlag(mode Flag) bool {
 	for _, m := range r.modes {
 		if m == mode {
 			return true
@@ -60,3 +132,68 @@ func (r *runtime) HasFlag(mode Flag) bool {
 	}
 	return false
 }
+
+func (r *runtime) Flags() []Flag {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return append([]Flag{}, r.modes...)
+}
+
+func (r *runtime) Info() RuntimeInfo {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.info
+}
+
+func (r *runtime) ResourceLimits() ResourceLimits {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.limits
+}
+
+// configuredFlags returns the flags from the BOOT_FLAGS configuration, before the runtime is initialized
+func configuredFlags(lookup func(key string) (string, bool)) []Flag {
+	value, _ := lookup("BOOT_FLAGS")
+	return parseFlags(value)
+}
+
+// parseFlags returns the comma separated flags
+func parseFlags(value string) []Flag {
+	var flags []Flag
+	for _, token := range strings.Split(value, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			flags = append(flags, Flag(token))
+		}
+	}
+	return flags
+}
+
+// newRuntimeInfo collects the facts about the application from the build info and the environment
+func newRuntimeInfo(instanceID string) RuntimeInfo {
+	info := RuntimeInfo{
+		Name:       filepath.Base(os.Args[0]),
+		InstanceID: instanceID,
+		StartTime:  time.Now(),
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if build.Main.Path != "" {
+			info.Name = build.Main.Path
+		}
+		info.Version = build.Main.Version
+		for _, setting := range build.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Revision = setting.Value
+			}
+		}
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		info.Hostname = hostname
+	}
+	if info.InstanceID == "" {
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err == nil {
+			info.InstanceID = hex.EncodeToString(id)
+		}
+	}
+	return info
+}
@@ -23,7 +23,11 @@
 
 package boot
 
-import "testing"
+import (
+	"os"
+	"testing"
+	"time"
+)
 
 func TestRuntimeHasMode(t *testing.T) {
 	type fields struct {
@@ -62,3 +66,75 @@ func TestRuntimeHasMode(t *testing.T) {
 		})
 	}
 }
+
+func TestRuntimeConfiguredFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []Flag
+	}{
+		{name: "none", value: "", want: []Flag{UnitTestFlag}},
+		{name: "additional", value: "functionalTest, canary", want: []Flag{UnitTestFlag, FunctionalTestFlag, "canary"}},
+		{name: "duplicate", value: "unitTest,canary,canary", want: []Flag{UnitTestFlag, "canary"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOOT_FLAGS", tt.value)
+			s := newTestSession()
+			if err := s.Go(); err != nil {
+				t.Fatalf("Go() error = %v", err)
+			}
+			got := s.runtime.Flags()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Flags() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] || !s.runtime.HasFlag(tt.want[i]) {
+					t.Errorf("Flags() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRuntimeTuning(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "default", want: true},
+		{name: "disabled", value: "false", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != "" {
+				t.Setenv("BOOT_RUNTIME_TUNING", tt.value)
+			}
+			s := newTestSession()
+			if err := s.Go(); err != nil {
+				t.Fatalf("Go() error = %v", err)
+			}
+			if s.runtime.Tuning != tt.want {
+				t.Errorf("Tuning = %v, want %v", s.runtime.Tuning, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuntimeInfo(t *testing.T) {
+	t.Setenv("BOOT_INSTANCE_ID", "instance-1")
+	hostname, _ := os.Hostname()
+	s := newTestSession()
+	before := time.Now()
+	if err := s.Go(); err != nil {
+		t.Fatalf("Go() error = %v", err)
+	}
+	info := s.runtime.Info()
+	if info.InstanceID != "instance-1" || info.Hostname != hostname || info.Name == "" || info.StartTime.Before(before) {
+		t.Errorf("Info() = %+v", info)
+	}
+	if generated := newRuntimeInfo(""); len(generated.InstanceID) != 16 {
+		t.Errorf("newRuntimeInfo() generated instance id %q", generated.InstanceID)
+	}
+}
@@ -24,19 +24,30 @@
 package boot
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"os/signal"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 )
 
 // factory contains a name, some metadata and factory function for a given component.
 type factory struct {
-	create   func() Component
-	name     string
-	override bool
+	create     func() Component
+	build      FactoryFunc
+	name       string
+	override   bool
+	conditions []Condition
+	// module is the name of the module, which contains the registration
+	module string
+	// instances is the configuration key, which contains the names of the instances to create
+	instances string
+	// configKey maps the configuration keys of the component. It may be nil.
+	configKey func(key string) string
 }
 
 // phase describes the status of the boot-go componentManager
@@ -70,8 +81,13 @@ const (
 var (
 	errSessionRegisterNameOrFunction             = errors.New("name and function for component factory registration is required")
 	errSessionRegisterComponentOutsideInitialize = errors.New("register component not allowed after boot has been started")
+	errSessionHandleSignalOutsideInitialize      = errors.New("handle signal not allowed after boot has been started")
+	errSessionSignalsNotHandled                  = errors.New("signals are not handled with DoMain or DoShutdown")
 )
 
+// shutdownTimeoutKey configures the ShutdownTimeout in milliseconds.
+const shutdownTimeoutKey = "BOOT_SHUTDOWN_TIMEOUT"
+
 const (
 	// initializing is set directly after the application started.
 	// In this phase it is safe to subscribe to events.
@@ -94,6 +110,125 @@ type Session struct {
 	runtime     *runtime
 	eventbus    *eventBus
 	option      Options
+	errors      *SessionError
+	registry    *registry
+	timeline    *timeline
+	listeners   *listenerRegistry
+	// signals contains the actions for all handled os signals
+	signals map[os.Signal]SignalAction
+	// shutdownRequest receives the request of Shutdown() when signals are handled
+	shutdownRequest chan struct{}
+	// modules contains all installed modules
+	modules []*moduleEntry
+	// excludedModules contains the names of the modules, which must not be used
+	excludedModules map[string]bool
+	// moduleConfig contains the configuration values of all modules, which aren't excluded
+	moduleConfig map[string]string
+	// parent is the session, which created this child session. It is nil for a root session.
+	parent *Session
+	// children contains the running child sessions
+	children children
+	// handle controls the session after it was started
+	handle *Handle
+	// dynamicMutex serializes adding and removing components while the session is running
+	dynamicMutex sync.Mutex
+}
+
+// ComponentError describes an error, which was returned by a component while the session was running.
+type ComponentError struct {
+	// Name is the full name of the failed component, e.g. default:github.com/boot-go/boot/eventBus
+	Name string
+	// Phase is the name of the session phase in which the error occurred.
+	Phase string
+	// Err is the error returned by the component.
+	Err error
+	// kind is ErrProcess, if the error was returned by Start or Stop of a Process. It may be nil.
+	kind error
+}
+
+// Error is used to confirm to the error interface
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("%s failed while %s: %s", e.Name, e.Phase, e.Err.Error())
+}
+
+// Unwrap returns the error returned by the component.
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrProcess, if the error was returned by Start or Stop of a Process.
+func (e *ComponentError) Is(target error) bool {
+	return e.kind != nil && target == e.kind //nolint:errorlint // sentinel comparison required
+}
+
+// SessionError contains all errors, which were returned asynchronously by the components
+// while the session was running, e.g. a failed Start() or Stop() of a Process.
+type SessionError struct {
+	mutex  sync.Mutex
+	errors []*ComponentError
+}
+
+var _ error = (*SessionError)(nil) // force error to confirm to error interface
+
+func newSessionError() *SessionError {
+	return &SessionError{}
+}
+
+// add appends a new component error. The kind is matched by the component error and may be nil.
+func (e *SessionError) add(name string, p phase, err error, kind error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.errors = append(e.errors, &ComponentError{
+		Name:  name,
+		Phase: p.String(),
+		Err:   err,
+		kind:  kind,
+	})
+}
+
+func (e *SessionError) hasErrors() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return len(e.errors) > 0
+}
+
+// Errors returns all component errors in the order of their occurrence.
+func (e *SessionError) Errors() []*ComponentError {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	errs := make([]*ComponentError, len(e.errors))
+	copy(errs, e.errors)
+	return errs
+}
+
+// Error is used to confirm to the error interface
+func (e *SessionError) Error() string {
+	errs := e.Errors()
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("session failed with %d error(s): %s", len(errs), strings.Join(msgs, "; "))
+}
+
+// Is reports whether any of the component errors matches the target.
+func (e *SessionError) Is(target error) bool {
+	for _, err := range e.Errors() {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first component error, which matches the target.
+func (e *SessionError) As(target any) bool {
+	for _, err := range e.Errors() {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
 }
 
 // Options contains the options for the boot-go Session
@@ -101,57 +236,78 @@ type Options struct {
 	// Mode is a list of flags to be used for the application.
 	Mode []Flag
 	// DoMain is called when the application is requested to start and blocks until shutdown is requested.
+	// If it is nil and no signals are bound, the session runs until Handle.Stop is called.
 	DoMain func() error
 	// DoShutdown is called when the application is requested to shutdown.
 	DoShutdown func() error
+	// Signals binds os signals to actions. The signals are only handled, if neither DoMain nor DoShutdown
+	// is provided. Then Shutdown() stops the session as well.
+	Signals map[os.Signal]SignalAction
+	// ShutdownTimeout limits the time to stop all components. Components, which are not stopped in time, will
+	// fail with ErrShutdownTimeout. There is no limit, if it is zero.
+	// It is configured by BOOT_SHUTDOWN_TIMEOUT in milliseconds, if it isn't set, e.g. for the session of Run.
+	ShutdownTimeout time.Duration
 	// channel to receive shutdown or interrupt signal - this is used for testing
 	shutdownChannel chan os.Signal
 }
 
-// NewSession will create a new Session with default options
+// NewSession will create a new Session with default options. SIGINT and SIGTERM will shut down the session.
 func NewSession(mode ...Flag) *Session {
-	localShutdownChannel := make(chan os.Signal, 1)
 	return NewSessionWithOptions(Options{
-		Mode: mode,
-		DoMain: func() error {
-			signal.Notify(localShutdownChannel, interruptSignal, shutdownSignal)
-			sig := <-localShutdownChannel
-			switch {
-			case sig == interruptSignal:
-				Logger.Warn.Printf("caught interrupt signal %s\n", sig.String())
-				Logger.Debug.Printf("shutdown gracefully initiated...\n")
-			case sig == shutdownSignal:
-				Logger.Debug.Printf("shutdown requested...\n")
-			}
-			return nil
-		},
-		DoShutdown: func() error {
-			localShutdownChannel <- shutdownSignal
-			return nil
-		},
-		shutdownChannel: localShutdownChannel,
+		Mode:    mode,
+		Signals: DefaultSignals(),
 	})
 }
 
-// NewSessionWithOptions will create a new Session with given options
+// NewSessionWithOptions will create a new Session with given options. If signals are bound and neither DoMain
+// nor DoShutdown is provided, the session runs until Shutdown() is called or a signal bound to ShutdownAction
+// is received.
 func NewSessionWithOptions(options Options) *Session {
+	return newSession(options, nil)
+}
+
+// newSession creates a new Session. A child session shares the runtime of its parent session, so the runtime
+// is initialized only once.
+func newSession(options Options, parent *Session) *Session {
 	s := &Session{
-		factories:   []factory{},
-		changeMutex: sync.Mutex{},
-		phase:       initializing,
-		option:      options,
+		factories:       []factory{},
+		changeMutex:     sync.Mutex{},
+		phase:           initializing,
+		option:          options,
+		errors:          newSessionError(),
+		timeline:        newTimeline(),
+		signals:         make(map[os.Signal]SignalAction),
+		excludedModules: make(map[string]bool),
+	}
+	for sig, action := range options.Signals {
+		s.signals[sig] = action
+	}
+	if len(options.Signals) > 0 {
+		s.installSignalHandling()
 	}
 	// register default components... errors not possible, so they are ignored
-	s.runtime = &runtime{
-		modes: options.Mode,
+	if parent != nil {
+		s.parent = parent
+		s.runtime = parent.runtime
+	} else {
+		s.runtime = &runtime{
+			modes: options.Mode,
+		}
+		_ = s.register(DefaultName, func() Component {
+			return s.runtime
+		}, false)
 	}
-	_ = s.register(DefaultName, func() Component {
-		return s.runtime
-	}, false)
 	s.eventbus = newEventbus()
 	_ = s.register(DefaultName, func() Component {
 		return s.eventbus
 	}, false)
+	_ = s.register(DefaultName, func() Component {
+		return newHealth(s)
+	}, false)
+	s.listeners = newListenerRegistry()
+	_ = s.register(DefaultName, func() Component {
+		return s.listeners
+	}, false)
 	return s
 }
 
@@ -181,63 +337,131 @@ func (s *Session) nextPhaseAfter(expected phase) error {
 }
 
 // register a factory function for a component. These functions will be called on boot to create the components.
-func (s *Session) register(name string, create func() Component, override bool) error {
+func (s *Session) register(name string, create func() Component, override bool, conditions ...Condition) error {
 	if name == "" || create == nil {
 		return errSessionRegisterNameOrFunction
 	}
+	return s.addFactory(factory{
+		create:     create,
+		name:       name,
+		override:   override,
+		conditions: conditions,
+	})
+}
+
+// addFactory adds the factory, if the session is initializing.
+func (s *Session) addFactory(f factory) error {
 	defer s.changeMutex.Unlock()
 	s.changeMutex.Lock()
 	if s.phase != initializing {
 		return errSessionRegisterComponentOutsideInitialize
 	}
-	s.factories = append(s.factories, factory{
-		create:   create,
-		name:     name,
-		override: override,
-	})
+	s.factories = append(s.factories, f)
 	return nil
 }
 
-// Register a factory function for a component. The component will be created on boot.
-func (s *Session) Register(create func() Component) error {
-	return s.register(DefaultName, create, false)
+// Register a factory function for a component. The component will be created on boot, if all conditions match.
+func (s *Session) Register(create func() Component, conditions ...Condition) error {
+	return s.register(DefaultName, create, false, conditions...)
 }
 
-// Override a factory function for a component. The component will be created on boot.
-func (s *Session) Override(create func() Component) error {
-	return s.register(DefaultName, create, true)
+// Override a factory function for a component. The component will be created on boot, if all conditions match.
+func (s *Session) Override(create func() Component, conditions ...Condition) error {
+	return s.register(DefaultName, create, true, conditions...)
 }
 
-// RegisterName registers a factory function with the given name. The component will be created on boot.
-func (s *Session) RegisterName(name string, create func() Component) error {
-	return s.register(name, create, false)
+// RegisterName registers a factory function with the given name. The component will be created on boot, if all
+// conditions match.
+func (s *Session) RegisterName(name string, create func() Component, conditions ...Condition) error {
+	return s.register(name, create, false, conditions...)
 }
 
-// OverrideName overrides a factory function with the given name. The component will be created on boot.
-func (s *Session) OverrideName(name string, create func() Component) error {
-	return s.register(name, create, true)
+// OverrideName overrides a factory function with the given name. The component will be created on boot, if all
+// conditions match.
+func (s *Session) OverrideName(name string, create func() Component, conditions ...Condition) error {
+	return s.register(name, create, true, conditions...)
 }
 
-// Go the boot component framework. This starts the execution process.
-func (s *Session) Go() error { //nolint:varnamelen // s is fine for method
-	if err := s.nextPhaseAfter(initializing); err != nil {
-		return err
-	}
+// RegisterAdmin registers the optional admin component, which exposes the internals of the session over
+// http. It listens on localhost:8081 by default, which can be changed with BOOT_ADMIN_HOST and BOOT_ADMIN_PORT.
+func (s *Session) RegisterAdmin() error {
+	return s.register(DefaultName, func() Component {
+		return newAdmin(s)
+	}, false)
+}
 
-	registry, err := s.createComponents()
+// RegisterControl registers the optional control component, which allows to operate the session over a
+// unix domain socket, e.g. with bootctl. The path of the socket can be changed with BOOT_CONTROL_SOCKET.
+func (s *Session) RegisterControl() error {
+	return s.register(DefaultName, func() Component {
+		return newControl(s)
+	}, false)
+}
+
+// RegisterSystemd registers the optional Systemd component, which notifies systemd about the session phases
+// and provides the listeners passed by socket activation.
+func (s *Session) RegisterSystemd() error {
+	return s.register(DefaultName, func() Component {
+		return newSystemd()
+	}, false)
+}
+
+// Go the boot component framework. This starts the execution process and blocks until all components
+// are stopped.
+func (s *Session) Go() error {
+	return s.GoContext(context.Background())
+}
+
+// GoContext the boot component framework. This starts the execution process and blocks until all components
+// are stopped. When the context is done, all components will be stopped gracefully.
+func (s *Session) GoContext(ctx context.Context) error {
+	h, err := s.Start(ctx)
 	if err != nil {
 		return err
 	}
-	instances, err := registry.resolveComponentDependencies()
+	go func() {
+		select {
+		case <-ctx.Done():
+			Logger.Debug.Printf("context done: %v", ctx.Err())
+			_ = h.Stop(context.Background())
+		case <-h.Done():
+		}
+	}()
+	return h.Wait()
+}
+
+// Start the boot component framework without blocking. Start returns as soon as all components are
+// initialized and started. The context is only used while starting; the returned Handle is used to wait
+// for or to stop the session. If booting fails or the context is done, the initialized components are set to
+// stopped and the session exits.
+func (s *Session) Start(ctx context.Context) (*Handle, error) { //nolint:varnamelen // s is fine for method
+	if err := s.nextPhaseAfter(initializing); err != nil {
+		return nil, err
+	}
+	s.publishEvent(BootingEvent{})
+
+	registry, instances, err := s.boot(ctx)
 	if err != nil {
-		return err
+		s.abortBoot()
+		return nil, err
 	}
 
 	if err := s.nextPhaseAfter(booting); err != nil {
-		return err
+		return nil, err
 	}
 	instances.startComponents()
 	Logger.Debug.Printf("%d components started", instances.count())
+	s.publishEvent(RunningEvent{})
+	h := newHandle(s, instances)
+	s.changeMutex.Lock()
+	s.handle = h
+	s.changeMutex.Unlock()
+	if s.parent != nil {
+		if err := s.parent.addChild(h); err != nil {
+			h.stop(nil)
+			return nil, err
+		}
+	}
 
 	go func() {
 		err := s.waitUntilAllComponentsStopped(registry)
@@ -248,29 +472,93 @@ func (s *Session) Go() error { //nolint:varnamelen // s is fine for method
 
 	// activate eventbus to process alle queued events
 	err = s.eventbus.activate()
-	if err == nil {
-		// blocking here until Shutdown
-		err = s.option.DoMain()
-		if err != nil {
-			Logger.Error.Printf("processing until shutdown failed with error: %v", err)
-			return err
+	for _, cm := range instances {
+		if info := cm.info(); !info.StartTime.IsZero() {
+			s.timeline.record(info.FullName(), StartStep, info.StartTime)
 		}
-	} else {
+	}
+	if err != nil {
 		Logger.Error.Printf("going down - eventbus activation failed: %v", err)
+		s.errors.add(DefaultName+":"+QualifiedName(s.eventbus), running, err, nil)
+		go h.stop(nil)
+		return h, nil
+	}
+	go h.run()
+	return h, nil
+}
+
+// boot creates, validates and initializes all components. The context is checked before and after the
+// components are initialized.
+func (s *Session) boot(ctx context.Context) (*registry, componentManagers, error) {
+	parentRegistry, err := s.parentRegistry()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.configureShutdownTimeout(); err != nil {
+		return nil, nil, err
+	}
+	registry, err := s.createComponents()
+	if err != nil {
+		return nil, nil, err
+	}
+	registry.parent = parentRegistry
+	s.changeMutex.Lock()
+	s.registry = registry
+	s.changeMutex.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	instances, err := registry.resolveComponentDependencies()
+	if err != nil {
+		return nil, nil, err
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return registry, instances, nil
+}
 
-	if err := s.nextPhaseAfter(running); err != nil {
-		Logger.Error.Printf("component stop error: %v", err)
+// abortBoot unwinds a session, which failed or was canceled while booting. The initialized components are
+// set to stopped, because they will never be started, and the session moves to exiting.
+func (s *Session) abortBoot() {
+	s.changeMutex.Lock()
+	Logger.Debug.Printf("boot phase changed from " + s.phase.String() + " to " + exiting.String())
+	s.phase = exiting
+	reg := s.registry
+	s.changeMutex.Unlock()
+	if reg != nil {
+		for _, cm := range reg.all() {
+			if state, _ := cm.getState(); state == Initialized {
+				cm.setState(Stopped, nil)
+			}
+		}
 	}
-	instances.stopComponents()
-	Logger.Debug.Printf("%d components stopped", instances.count())
+	s.listeners.closeInherited()
+	s.publishEvent(ExitingEvent{})
+}
 
-	if err := s.nextPhaseAfter(stopping); err != nil {
-		return err
+// Components returns a snapshot of all components sorted by their full name. It is empty until the
+// session was started.
+func (s *Session) Components() []ComponentInfo {
+	s.changeMutex.Lock()
+	reg := s.registry
+	s.changeMutex.Unlock()
+	if reg == nil {
+		return nil
 	}
+	return reg.components()
+}
 
-	Logger.Debug.Printf("boot done")
-	return nil
+// Configuration returns all configuration values, which were injected into the components. The values of
+// secrets are redacted. It is empty until the session was started.
+func (s *Session) Configuration() []ConfigValue {
+	s.changeMutex.Lock()
+	reg := s.registry
+	s.changeMutex.Unlock()
+	if reg == nil {
+		return nil
+	}
+	return reg.configuration()
 }
 
 // Shutdown initiates the shutdown process. All components will be stopped.
@@ -288,19 +576,174 @@ func (s *Session) Shutdown() error {
 // createComponents() will create all registered components
 func (s *Session) createComponents() (*registry, error) {
 	registry := newRegistry()
-	for _, factory := range s.factories {
-		component := factory.create()
-		if component == nil {
-			return nil, fmt.Errorf("factory %s failed to create a component", QualifiedName(factory))
+	registry.changed = s.componentStateChanged
+	registry.timeline = s.timeline
+	registry.lookup = s.lookupConfig
+	registry.eventbus = s.eventbus
+	excluded := s.prepareModules()
+	ctx := &conditionContext{
+		flags:    append(append([]Flag{}, s.option.Mode...), configuredFlags(s.lookupConfig)...),
+		registry: registry,
+		lookup:   s.lookupConfig,
+	}
+	// registrations with conditions on other components are created after all others
+	var deferred []factory
+	for _, factory := range s.expandInstances() {
+		if excluded[factory.module] {
+			Logger.Debug.Printf("registration %s:%s skipped - module %s excluded", factory.name, factory.qualifiedName(), factory.module)
+			continue
 		}
-		err := registry.addItem(factory.name, factory.override, component)
-		if err != nil {
+		if !factory.matches(ctx, false) {
+			continue
+		}
+		if factory.hasComponentConditions() {
+			deferred = append(deferred, factory)
+			continue
+		}
+		if err := s.createComponent(registry, factory, ctx.flags); err != nil {
+			return registry, err
+		}
+	}
+	for _, factory := range deferred {
+		if !factory.matches(ctx, true) {
+			continue
+		}
+		if err := s.createComponent(registry, factory, ctx.flags); err != nil {
 			return registry, err
 		}
 	}
 	return registry, nil
 }
 
+// createComponent calls the factory and adds the component to the registry
+func (s *Session) createComponent(registry *registry, factory factory, flags []Flag) error {
+	factoryStart := time.Now()
+	ctx := &factoryContext{
+		name:      factory.name,
+		configKey: factory.configKey,
+		lookup:    s.lookupConfig,
+		flags:     flags,
+	}
+	component, err := factory.newComponent(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.err == nil && ctx.binding != nil && ctx.binding.component != component {
+		ctx.err = errFactoryBindingMismatch
+	}
+	if ctx.err != nil {
+		return &FactoryError{Name: factory.name, Factory: factory.qualifiedName(), Err: ctx.err}
+	}
+	fullName := factory.name + ":" + QualifiedName(component)
+	s.timeline.record(fullName, FactoryStep, factoryStart)
+	if err := registry.addItem(factory.name, factory.override, component); err != nil {
+		return err
+	}
+	cm := registry.item(fullName)
+	cm.configKey = factory.configKey
+	cm.binding = ctx.binding
+	return nil
+}
+
+// configureShutdownTimeout sets the ShutdownTimeout from BOOT_SHUTDOWN_TIMEOUT, unless it was set by the options.
+func (s *Session) configureShutdownTimeout() error {
+	if s.option.ShutdownTimeout > 0 {
+		return nil
+	}
+	value, ok := s.lookupConfig(shutdownTimeoutKey)
+	if !ok || value == "" {
+		return nil
+	}
+	millis, err := strconv.Atoi(value)
+	if err != nil || millis < 0 {
+		return fmt.Errorf("%w: invalid %s %q", ErrConfiguration, shutdownTimeoutKey, value)
+	}
+	s.option.ShutdownTimeout = time.Duration(millis) * time.Millisecond
+	return nil
+}
+
+// stopComponents() stops all components in reverse order. If a ShutdownTimeout is set, the component which
+// couldn't be stopped in time and all processes, which weren't stopped yet, will be reported with
+// ErrShutdownTimeout. After the timeout, no further components will be stopped.
+func (s *Session) stopComponents(instances componentManagers) {
+	if s.option.ShutdownTimeout <= 0 {
+		instances.stopComponents()
+		return
+	}
+	mutex := sync.Mutex{}
+	pending := len(instances) // instances[:pending] are not stopped yet
+	timedOut := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			mutex.Lock()
+			if timedOut || pending == 0 {
+				mutex.Unlock()
+				return
+			}
+			current := instances[pending-1]
+			mutex.Unlock()
+			current.stop()
+			mutex.Lock()
+			pending--
+			mutex.Unlock()
+		}
+	}()
+	timer := time.NewTimer(s.option.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		Logger.Error.Printf("stopping components exceeded the shutdown timeout of %s", s.option.ShutdownTimeout)
+		mutex.Lock()
+		timedOut = true
+		remaining := instances[:pending]
+		mutex.Unlock()
+		for i := range remaining {
+			cm := remaining[len(remaining)-i-1]
+			if _, ok := cm.component.(Process); !ok {
+				continue
+			}
+			// the state of the component currently stopping is locked until its Stop() returns
+			if i > 0 {
+				if state, _ := cm.getState(); state != Started {
+					continue
+				}
+			}
+			s.errors.add(cm.getFullName(), stopping, ErrShutdownTimeout, nil)
+		}
+	}
+}
+
+// componentStateChanged() publishes the new state of a component and collects the error of a component,
+// which failed while the session was running or stopping. Errors while booting and validation or initialization
+// errors of components added while running are returned directly. The remaining errors were returned by Start
+// or Stop of a Process and match ErrProcess.
+func (s *Session) componentStateChanged(cm *componentManager, state ComponentState, err error) {
+	p := s.currentPhase()
+	var invalid *validationFailure
+	if err != nil && p >= running && !errors.Is(err, ErrInitialization) && !errors.As(err, &invalid) {
+		var kind error
+		if _, ok := cm.component.(Process); ok {
+			kind = ErrProcess
+		}
+		s.errors.add(cm.getFullName(), p, err, kind)
+	}
+	s.publishEvent(ComponentEvent{
+		Name:  cm.getFullName(),
+		State: state,
+		Err:   err,
+	})
+}
+
+// currentPhase returns the phase of the session
+func (s *Session) currentPhase() phase {
+	s.changeMutex.Lock()
+	defer s.changeMutex.Unlock()
+	return s.phase
+}
+
 // waitUntilAllComponentsStopped() will wait until all components have stopped processing
 func (s *Session) waitUntilAllComponentsStopped(reg *registry) error {
 	Logger.Debug.Printf("wait until all components are stopped...")
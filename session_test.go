@@ -24,8 +24,10 @@
 package boot
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestSessionNextPhaseAfter(t *testing.T) {
@@ -363,7 +365,7 @@ func TestSessionRunEventbusActivationFail(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		wantErr error
+		wantErr bool
 	}{
 		{
 			name: "successful",
@@ -374,7 +376,7 @@ func TestSessionRunEventbusActivationFail(t *testing.T) {
 					}
 				},
 			},
-			wantErr: nil,
+			wantErr: false,
 		},
 		{
 			name: "successful process component",
@@ -385,7 +387,7 @@ func TestSessionRunEventbusActivationFail(t *testing.T) {
 					}
 				},
 			},
-			wantErr: nil,
+			wantErr: false,
 		},
 		{
 			name: "unsuccessful",
@@ -396,7 +398,7 @@ func TestSessionRunEventbusActivationFail(t *testing.T) {
 					}
 				},
 			},
-			wantErr: nil,
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -407,7 +409,8 @@ func TestSessionRunEventbusActivationFail(t *testing.T) {
 				t.Fail()
 			}
 			err = s.Go()
-			if err != tt.wantErr { //nolint:errorlint // using errors.Is(..) will fail test
+			var pubErr *PublishError
+			if (err != nil) != tt.wantErr || (tt.wantErr && !errors.As(err, &pubErr)) {
 				t.Errorf("Go() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -519,3 +522,104 @@ func TestSessionWithOptionsFailOnDoMain(t *testing.T) {
 		}
 	})
 }
+
+type sessionErrorTest struct {
+	startErr error
+	stopErr  error
+	block    chan struct{}
+}
+
+func (c *sessionErrorTest) Init() error {
+	c.block = make(chan struct{})
+	return nil
+}
+
+func (c *sessionErrorTest) Start() error {
+	if c.startErr != nil {
+		return c.startErr
+	}
+	<-c.block
+	return nil
+}
+
+func (c *sessionErrorTest) Stop() error {
+	close(c.block)
+	return c.stopErr
+}
+
+func TestSessionGoReturnsSessionError(t *testing.T) {
+	errStart := errors.New("start failed")
+	errStop := errors.New("stop failed")
+	tests := []struct {
+		name      string
+		component *sessionErrorTest
+		shutdown  bool
+		wantErr   error
+		wantPhase string
+	}{
+		{name: "start fails", component: &sessionErrorTest{startErr: errStart}, wantErr: errStart, wantPhase: "running"},
+		{name: "stop fails", component: &sessionErrorTest{stopErr: errStop}, shutdown: true, wantErr: errStop, wantPhase: "stopping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession(tt.component)
+			if tt.shutdown {
+				go func() {
+					time.Sleep(time.Second)
+					_ = s.Shutdown()
+				}()
+			}
+			err := s.Go()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Go() error = %v, want %v", err, tt.wantErr)
+			}
+			var sessionErr *SessionError
+			if !errors.As(err, &sessionErr) {
+				t.Fatalf("Go() error = %v is not a SessionError", err)
+			}
+			var cmpErr *ComponentError
+			if !errors.As(err, &cmpErr) {
+				t.Fatalf("Go() error = %v doesn't contain a ComponentError", err)
+			}
+			if len(sessionErr.Errors()) != 1 ||
+				cmpErr.Name != "default:github.com/boot-go/boot/sessionErrorTest" ||
+				cmpErr.Phase != tt.wantPhase {
+				t.Errorf("unexpected component error %v", cmpErr)
+			}
+		})
+	}
+}
+
+func TestSessionGoContext(t *testing.T) {
+	t.Run("cancel", func(t *testing.T) {
+		s := newTestSession(&bootProcessesComponent{})
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(time.Second)
+			cancel()
+		}()
+		if err := s.GoContext(ctx); err != nil {
+			t.Errorf("GoContext() error = %v", err)
+		}
+	})
+	t.Run("deadline", func(t *testing.T) {
+		s := newTestSessionWithOptions(Options{
+			DoMain: func() error {
+				select {}
+			},
+		}, &bootProcessesComponent{})
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := s.GoContext(ctx); err != nil {
+			t.Errorf("GoContext() error = %v", err)
+		}
+	})
+	t.Run("already canceled", func(t *testing.T) {
+		s := newTestSession(&bootProcessesComponent{})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := s.GoContext(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("GoContext() error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
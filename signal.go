@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	gort "runtime"
+	"runtime/pprof"
+)
+
+// SignalAction describes the reaction of the session on a received os signal.
+type SignalAction int
+
+const (
+	// ShutdownAction stops all components gracefully and publishes a ShutdownEvent.
+	ShutdownAction SignalAction = iota
+	// ImmediateShutdownAction ends the session without stopping the components and publishes a ShutdownEvent.
+	ImmediateShutdownAction
+	// ReloadAction publishes a ReloadEvent, so components can reload their configuration.
+	ReloadAction
+	// DiagnosticsAction writes diagnostic information to the info logger and publishes a DiagnosticsEvent.
+	DiagnosticsAction
+)
+
+// String returns the name of the signal action
+func (a SignalAction) String() string {
+	switch a {
+	case ShutdownAction:
+		return "shutdown"
+	case ImmediateShutdownAction:
+		return "immediate shutdown"
+	case ReloadAction:
+		return "reload"
+	case DiagnosticsAction:
+		return "diagnostics"
+	}
+	return "unknown"
+}
+
+// ShutdownEvent is published when a signal bound to ShutdownAction or ImmediateShutdownAction was received.
+type ShutdownEvent struct {
+	Signal    os.Signal
+	Immediate bool
+}
+
+// ReloadEvent is published when a signal bound to ReloadAction was received. The Signal is nil, if the reload
+// was requested over the control socket.
+type ReloadEvent struct {
+	Signal os.Signal
+}
+
+// DiagnosticsEvent is published when a signal bound to DiagnosticsAction was received.
+type DiagnosticsEvent struct {
+	Signal os.Signal
+}
+
+// ErrImmediateShutdown is returned when the session was ended by a signal bound to ImmediateShutdownAction.
+var ErrImmediateShutdown = errors.New("immediate shutdown requested")
+
+// DefaultSignals returns the signals used by NewSession. SIGINT and SIGTERM will shut down the session.
+func DefaultSignals() map[os.Signal]SignalAction {
+	return map[os.Signal]SignalAction{
+		interruptSignal: ShutdownAction,
+		shutdownSignal:  ShutdownAction,
+	}
+}
+
+// HandleSignal binds the signal to the action. E.g. HandleSignal(syscall.SIGHUP, ReloadAction) will publish
+// a ReloadEvent whenever SIGHUP is received. The signals are only handled, if the session was created
+// without DoMain and DoShutdown, otherwise an error is returned. A session, which didn't handle signals
+// so far, runs until Shutdown() is called or a signal bound to ShutdownAction is received afterwards.
+func (s *Session) HandleSignal(sig os.Signal, action SignalAction) error {
+	defer s.changeMutex.Unlock()
+	s.changeMutex.Lock()
+	if s.phase != initializing {
+		return errSessionHandleSignalOutsideInitialize
+	}
+	if !s.installSignalHandling() {
+		return errSessionSignalsNotHandled
+	}
+	s.signals[sig] = action
+	return nil
+}
+
+// installSignalHandling uses the signal handling as DoMain and DoShutdown. It returns false, if the session
+// was created with DoMain or DoShutdown.
+func (s *Session) installSignalHandling() bool {
+	if s.shutdownRequest != nil {
+		return true
+	}
+	if s.option.DoMain != nil || s.option.DoShutdown != nil {
+		return false
+	}
+	s.option.shutdownChannel = make(chan os.Signal, 1)
+	s.shutdownRequest = make(chan struct{}, 1)
+	s.option.DoMain = s.handleSignals
+	s.option.DoShutdown = s.requestShutdown
+	return true
+}
+
+// handleSignals blocks until a signal bound to a shutdown action is received or Shutdown() is called.
+func (s *Session) handleSignals() error {
+	signals := make([]os.Signal, 0, len(s.signals))
+	for sig := range s.signals {
+		signals = append(signals, sig)
+	}
+	if len(signals) > 0 {
+		signal.Notify(s.option.shutdownChannel, signals...)
+		defer signal.Stop(s.option.shutdownChannel)
+	}
+	for {
+		select {
+		case sig := <-s.option.shutdownChannel:
+			if done, err := s.handleSignal(sig); done {
+				return err
+			}
+		case <-s.shutdownRequest:
+			Logger.Debug.Printf("shutdown requested...\n")
+			return nil
+		}
+	}
+}
+
+// handleSignal executes the action bound to the signal. It returns true, if the session must be shut down.
+func (s *Session) handleSignal(sig os.Signal) (bool, error) {
+	action, ok := s.signals[sig]
+	if !ok {
+		Logger.Warn.Printf("caught unhandled signal %s\n", sig.String())
+		return false, nil
+	}
+	Logger.Warn.Printf("caught signal %s - %s initiated...\n", sig.String(), action.String())
+	switch action {
+	case ShutdownAction:
+		s.publishEvent(ShutdownEvent{Signal: sig})
+		return true, nil
+	case ImmediateShutdownAction:
+		s.publishEvent(ShutdownEvent{Signal: sig, Immediate: true})
+		return true, ErrImmediateShutdown
+	case ReloadAction:
+		s.publishEvent(ReloadEvent{Signal: sig})
+	case DiagnosticsAction:
+		s.dumpDiagnostics()
+		s.publishEvent(DiagnosticsEvent{Signal: sig})
+	}
+	return false, nil
+}
+
+// requestShutdown is used as DoShutdown, when the signals are handled by the session.
+func (s *Session) requestShutdown() error {
+	select {
+	case s.shutdownRequest <- struct{}{}:
+	default:
+		// a shutdown is already pending
+	}
+	return nil
+}
+
+// dumpDiagnostics writes the session phase, the memory statistics and the stack of all goroutines to the info logger.
+func (s *Session) dumpDiagnostics() {
+	p := s.currentPhase()
+	stats := new(gort.MemStats)
+	gort.ReadMemStats(stats)
+	w := Logger.Info.Writer()
+	_, _ = fmt.Fprintf(w, "diagnostics /// PHASE/%s GOROUTINES/%d ALLOC/%d SYS/%d GC/%d\n",
+		p.String(), gort.NumGoroutine(), stats.Alloc, stats.Sys, stats.NumGC)
+	if err := pprof.Lookup("goroutine").WriteTo(w, 1); err != nil {
+		Logger.Error.Printf("writing goroutines failed: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSignal is a signal, which is never sent by the os
+type testSignal string
+
+func (s testSignal) String() string { return string(s) }
+
+func (s testSignal) Signal() {}
+
+type signalEventTest struct {
+	Eventbus EventBus `boot:"wire"`
+	mutex    sync.Mutex
+	events   []Event
+}
+
+func (c *signalEventTest) Init() error {
+	handler := func(e Event) {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		c.events = append(c.events, e)
+	}
+	_ = c.Eventbus.Subscribe(func(e ShutdownEvent) { handler(e) })
+	_ = c.Eventbus.Subscribe(func(e ReloadEvent) { handler(e) })
+	_ = c.Eventbus.Subscribe(func(e DiagnosticsEvent) { handler(e) })
+	return nil
+}
+
+func (c *signalEventTest) received() []Event {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]Event(nil), c.events...)
+}
+
+func TestSessionHandleSignal(t *testing.T) {
+	reload := testSignal("reload")
+	diagnostics := testSignal("diagnostics")
+	quit := testSignal("quit")
+	stop := testSignal("stop")
+	unknown := testSignal("unknown")
+	tests := []struct {
+		name    string
+		signals []os.Signal
+		want    []Event
+		wantErr error
+	}{
+		{
+			name:    "graceful shutdown",
+			signals: []os.Signal{unknown, reload, diagnostics, stop},
+			want:    []Event{ReloadEvent{Signal: reload}, DiagnosticsEvent{Signal: diagnostics}, ShutdownEvent{Signal: stop}},
+		},
+		{
+			name:    "immediate shutdown",
+			signals: []os.Signal{quit},
+			want:    []Event{ShutdownEvent{Signal: quit, Immediate: true}},
+			wantErr: ErrImmediateShutdown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmp := &signalEventTest{}
+			s := newTestSessionWithOptions(Options{
+				Signals: map[os.Signal]SignalAction{stop: ShutdownAction},
+			}, cmp, &bootProcessesComponent{})
+			_ = s.HandleSignal(reload, ReloadAction)
+			_ = s.HandleSignal(diagnostics, DiagnosticsAction)
+			_ = s.HandleSignal(quit, ImmediateShutdownAction)
+			go func() {
+				time.Sleep(time.Second)
+				for _, sig := range tt.signals {
+					s.option.shutdownChannel <- sig
+				}
+			}()
+			if err := s.Go(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Go() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := cmp.received()
+			if len(got) != len(tt.want) {
+				t.Fatalf("received events %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("received event %v, want %v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSessionHandleSignalWithoutSignals(t *testing.T) {
+	reload := testSignal("reload")
+	stop := testSignal("stop")
+	cmp := &signalEventTest{}
+	s := newTestSessionWithOptions(Options{}, cmp, &bootProcessesComponent{})
+	if err := s.HandleSignal(reload, ReloadAction); err != nil {
+		t.Fatalf("HandleSignal() error = %v", err)
+	}
+	if err := s.HandleSignal(stop, ShutdownAction); err != nil {
+		t.Fatalf("HandleSignal() error = %v", err)
+	}
+	go func() {
+		time.Sleep(time.Second)
+		s.option.shutdownChannel <- reload
+		s.option.shutdownChannel <- stop
+	}()
+	if err := s.Go(); err != nil {
+		t.Fatalf("Go() error = %v", err)
+	}
+	want := []Event{ReloadEvent{Signal: reload}, ShutdownEvent{Signal: stop}}
+	if got := cmp.received(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("received events %v, want %v", got, want)
+	}
+}
+
+func TestSessionHandleSignalWithDoMain(t *testing.T) {
+	s := newTestSessionWithOptions(Options{DoMain: func() error { return nil }})
+	if err := s.HandleSignal(testSignal("reload"), ReloadAction); !errors.Is(err, errSessionSignalsNotHandled) {
+		t.Errorf("HandleSignal() error = %v, want %v", err, errSessionSignalsNotHandled)
+	}
+}
+
+func TestSessionHandleSignalAfterStart(t *testing.T) {
+	s := newTestSession()
+	s.phase = running
+	if err := s.HandleSignal(testSignal("reload"), ReloadAction); !errors.Is(err, errSessionHandleSignalOutsideInitialize) {
+		t.Errorf("HandleSignal() error = %v, want %v", err, errSessionHandleSignalOutsideInitialize)
+	}
+}
+
+func TestSignalActionString(t *testing.T) {
+	tests := []struct {
+		action SignalAction
+		want   string
+	}{
+		{action: ShutdownAction, want: "shutdown"},
+		{action: ImmediateShutdownAction, want: "immediate shutdown"},
+		{action: ReloadAction, want: "reload"},
+		{action: DiagnosticsAction, want: "diagnostics"},
+		{action: -1, want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.action.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd socket activation
+var listenFdsStart = 3
+
+// Systemd is an optional component, which integrates the session into systemd. It notifies systemd about
+// the session phases, sends watchdog keepalives and provides the listeners passed by socket activation.
+type Systemd interface {
+	// Notify sends the state to systemd, e.g. STATUS=connected. Nothing is sent, if the service wasn't
+	// started by systemd with NOTIFY_SOCKET.
+	Notify(state string) error
+	// Listener returns a listener passed by socket activation with the given FileDescriptorName. Every
+	// listener is only returned once and the caller is responsible to close it.
+	Listener(name string) (net.Listener, bool)
+}
+
+// systemd is the implementation of the Systemd component.
+type systemd struct {
+	Eventbus  EventBus `boot:"wire"`
+	Health    Health   `boot:"wire"`
+	mutex     sync.Mutex
+	socket    string
+	watchdog  time.Duration
+	listeners map[string][]net.Listener
+	done      chan struct{}
+	stopOnce  sync.Once
+}
+
+var _ Process = (*systemd)(nil) // Verify conformity to Process
+
+var _ Systemd = (*systemd)(nil) // Verify conformity to Systemd
+
+// errSystemdWatchdogPid is returned when the watchdog is meant for another process
+var errSystemdWatchdogPid = errors.New("watchdog is enabled for another process")
+
+func newSystemd() *systemd {
+	return &systemd{
+		listeners: make(map[string][]net.Listener),
+		done:      make(chan struct{}),
+	}
+}
+
+// Init is described in the Component interface
+func (s *systemd) Init() error {
+	s.socket = os.Getenv("NOTIFY_SOCKET")
+	if strings.HasPrefix(s.socket, "@") {
+		// abstract socket namespace
+		s.socket = "\x00" + s.socket[1:]
+	}
+	watchdog, err := watchdogInterval()
+	if err != nil && !errors.Is(err, errSystemdWatchdogPid) {
+		return err
+	}
+	s.watchdog = watchdog
+	if err := s.activateListeners(); err != nil {
+		return err
+	}
+	if err := s.Eventbus.Subscribe(func(event RunningEvent) {
+		s.notify("READY=1")
+	}); err != nil {
+		return err
+	}
+	return s.Eventbus.Subscribe(func(event StoppingEvent) {
+		s.notify("STOPPING=1")
+	})
+}
+
+// Start is described in the Process interface. It sends the watchdog keepalives as long as the session is
+// alive.
+func (s *systemd) Start() error {
+	if s.watchdog <= 0 {
+		<-s.done
+		return nil
+	}
+	Logger.Debug.Printf("sending systemd watchdog keepalives every %s", s.watchdog/2)
+	ticker := time.NewTicker(s.watchdog / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return nil
+		case <-ticker.C:
+			if report := s.Health.Liveness(context.Background()); report.Status != HealthDown {
+				s.notify("WATCHDOG=1")
+			}
+		}
+	}
+}
+
+// Stop is described in the Process interface. Listeners, which weren't requested, will be closed.
+func (s *systemd) Stop() error {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for name, listeners := range s.listeners {
+		for _, listener := range listeners {
+			_ = listener.Close()
+		}
+		delete(s.listeners, name)
+	}
+	return nil
+}
+
+// Notify is described in the Systemd interface
+func (s *systemd) Notify(state string) error {
+	if s.socket == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: s.socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// Listener is described in the Systemd interface
+func (s *systemd) Listener(name string) (net.Listener, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	listeners := s.listeners[name]
+	if len(listeners) == 0 {
+		return nil, false
+	}
+	s.listeners[name] = listeners[1:]
+	return listeners[0], true
+}
+
+// notify sends the state and logs a failure, because systemd must not interrupt the session
+func (s *systemd) notify(state string) {
+	if err := s.Notify(state); err != nil {
+		Logger.Error.Printf("systemd notify %s failed: %v", state, err)
+	}
+}
+
+// activateListeners takes over the listeners passed by socket activation. The environment variables are
+// removed, so they aren't inherited by child processes.
+func (s *systemd) activateListeners() error {
+	defer func() {
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+	}()
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	for i := 0; i < count; i++ {
+		name := "unknown"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		file := os.NewFile(uintptr(listenFdsStart+i), name)
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			Logger.Warn.Printf("ignoring socket activated file descriptor %d (%s): %v", listenFdsStart+i, name, err)
+			continue
+		}
+		Logger.Debug.Printf("socket activated listener %s on %s", name, listener.Addr())
+		s.listeners[name] = append(s.listeners[name], listener)
+	}
+	return nil
+}
+
+// watchdogInterval returns the watchdog interval requested by systemd or zero, if the watchdog is disabled
+func watchdogInterval() (time.Duration, error) {
+	value := os.Getenv("WATCHDOG_USEC")
+	if value == "" {
+		return 0, nil
+	}
+	usec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, errors.New("invalid WATCHDOG_USEC " + value)
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, errSystemdWatchdogPid
+	}
+	return time.Duration(usec) * time.Microsecond, nil
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// TimelineStep names a lifecycle step of a component.
+type TimelineStep string
+
+const (
+	// FactoryStep is the call of the registered factory function.
+	FactoryStep TimelineStep = "factory"
+	// ConfigStep is the injection of all configuration values.
+	ConfigStep TimelineStep = "config"
+	// InitStep is the call of Init().
+	InitStep TimelineStep = "init"
+	// StartStep lasts from the call of Start() until the session is running.
+	StartStep TimelineStep = "start"
+	// StopStep is the call of Stop().
+	StopStep TimelineStep = "stop"
+)
+
+// TimelineEntry describes the duration of one lifecycle step of a component.
+type TimelineEntry struct {
+	// Component is the full name of the component
+	Component string
+	// Step is the lifecycle step
+	Step TimelineStep
+	// Start is the time when the step was started
+	Start time.Time
+	// Duration is the time spent in the step
+	Duration time.Duration
+}
+
+// Timeline contains the lifecycle steps of all components in the order of their occurrence.
+type Timeline []TimelineEntry
+
+// timeline records the lifecycle steps of the components.
+type timeline struct {
+	mutex   sync.Mutex
+	entries Timeline
+}
+
+func newTimeline() *timeline {
+	return &timeline{}
+}
+
+// record adds a step, which started at the given time and ends now. It does nothing on a nil timeline.
+func (t *timeline) record(component string, step TimelineStep, start time.Time) {
+	if t == nil {
+		return
+	}
+	t.recordDuration(component, step, start, time.Since(start))
+}
+
+// recordDuration adds a step with the given duration. It does nothing on a nil timeline.
+func (t *timeline) recordDuration(component string, step TimelineStep, start time.Time, duration time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.entries = append(t.entries, TimelineEntry{
+		Component: component,
+		Step:      step,
+		Start:     start,
+		Duration:  duration,
+	})
+}
+
+// snapshot returns a copy of all recorded steps
+func (t *timeline) snapshot() Timeline {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append(Timeline(nil), t.entries...)
+}
+
+// chromeTraceEvent is a complete event of the Chrome trace event format.
+type chromeTraceEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur"`
+	ProcessID int               `json:"pid"`
+	ThreadID  int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the timeline in the Chrome trace event format, which can be loaded into
+// chrome://tracing or https://ui.perfetto.dev. Every component is shown as a separate thread.
+func (t Timeline) WriteChromeTrace(w io.Writer) error {
+	begin := t.begin()
+	threads := make(map[string]int)
+	events := make([]chromeTraceEvent, 0, len(t))
+	for _, entry := range t {
+		tid, ok := threads[entry.Component]
+		if !ok {
+			tid = len(threads) + 1
+			threads[entry.Component] = tid
+		}
+		events = append(events, chromeTraceEvent{
+			Name:      entry.Component + " " + string(entry.Step),
+			Category:  string(entry.Step),
+			Phase:     "X",
+			Timestamp: entry.Start.Sub(begin).Microseconds(),
+			Duration:  entry.Duration.Microseconds(),
+			ProcessID: 1,
+			ThreadID:  tid,
+			Args:      map[string]string{"component": entry.Component},
+		})
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeTraceEvent `json:"traceEvents"`
+		DisplayTimeUnit string             `json:"displayTimeUnit"`
+	}{
+		TraceEvents:     events,
+		DisplayTimeUnit: "ms",
+	})
+}
+
+// WriteTable writes the timeline as a text table, which is sorted by the duration of the steps in
+// descending order. The offset is relative to the first recorded step.
+func (t Timeline) WriteTable(w io.Writer) error {
+	begin := t.begin()
+	sorted := append(Timeline(nil), t...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "DURATION\tOFFSET\tSTEP\tCOMPONENT")
+	for _, entry := range sorted {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.Duration, entry.Start.Sub(begin), entry.Step, entry.Component)
+	}
+	return tw.Flush()
+}
+
+// begin returns the start of the earliest step
+func (t Timeline) begin() time.Time {
+	var begin time.Time
+	for _, entry := range t {
+		if begin.IsZero() || entry.Start.Before(begin) {
+			begin = entry.Start
+		}
+	}
+	return begin
+}
+
+// Timeline returns the recorded lifecycle steps of all components.
+func (s *Session) Timeline() Timeline {
+	return s.timeline.snapshot()
+}
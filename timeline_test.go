@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type timelineTest struct {
+	Value string `boot:"config,key:TIMELINE_TEST_VALUE,default:value"`
+}
+
+func (c *timelineTest) Init() error {
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+func TestSessionTimeline(t *testing.T) {
+	s := newTestSession(&timelineTest{}, &bootProcessesComponent{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	steps := make(map[string]TimelineEntry)
+	for _, entry := range s.Timeline() {
+		steps[entry.Component+" "+string(entry.Step)] = entry
+	}
+	for _, want := range []string{
+		"default:github.com/boot-go/boot/timelineTest factory",
+		"default:github.com/boot-go/boot/timelineTest config",
+		"default:github.com/boot-go/boot/timelineTest init",
+		"default:github.com/boot-go/boot/bootProcessesComponent start",
+		"default:github.com/boot-go/boot/bootProcessesComponent stop",
+	} {
+		if _, ok := steps[want]; !ok {
+			t.Errorf("timeline doesn't contain %s", want)
+		}
+	}
+	if d := steps["default:github.com/boot-go/boot/timelineTest init"].Duration; d < 20*time.Millisecond {
+		t.Errorf("init duration = %s, want at least 20ms", d)
+	}
+}
+
+func TestTimelineWriteChromeTrace(t *testing.T) {
+	begin := time.Now()
+	timeline := Timeline{
+		{Component: "default:a", Step: InitStep, Start: begin, Duration: 2 * time.Millisecond},
+		{Component: "default:b", Step: InitStep, Start: begin.Add(3 * time.Millisecond), Duration: time.Millisecond},
+	}
+	buf := &bytes.Buffer{}
+	if err := timeline.WriteChromeTrace(buf); err != nil {
+		t.Fatalf("WriteChromeTrace() error = %v", err)
+	}
+	var trace struct {
+		TraceEvents []chromeTraceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("invalid chrome trace: %v", err)
+	}
+	want := []chromeTraceEvent{
+		{Name: "default:a init", Category: "init", Phase: "X", Timestamp: 0, Duration: 2000, ProcessID: 1, ThreadID: 1, Args: map[string]string{"component": "default:a"}},
+		{Name: "default:b init", Category: "init", Phase: "X", Timestamp: 3000, Duration: 1000, ProcessID: 1, ThreadID: 2, Args: map[string]string{"component": "default:b"}},
+	}
+	if len(trace.TraceEvents) != len(want) {
+		t.Fatalf("trace events = %v, want %v", trace.TraceEvents, want)
+	}
+	for i, event := range trace.TraceEvents {
+		if event.Name != want[i].Name || event.Phase != want[i].Phase || event.Timestamp != want[i].Timestamp ||
+			event.Duration != want[i].Duration || event.ThreadID != want[i].ThreadID || event.Args["component"] != want[i].Args["component"] {
+			t.Errorf("trace event = %v, want %v", event, want[i])
+		}
+	}
+}
+
+func TestTimelineWriteTable(t *testing.T) {
+	begin := time.Now()
+	timeline := Timeline{
+		{Component: "default:a", Step: InitStep, Start: begin, Duration: time.Millisecond},
+		{Component: "default:b", Step: StopStep, Start: begin.Add(time.Millisecond), Duration: 5 * time.Millisecond},
+	}
+	buf := &bytes.Buffer{}
+	if err := timeline.WriteTable(buf); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 ||
+		!strings.HasPrefix(lines[0], "DURATION") ||
+		!strings.HasSuffix(lines[1], "stop  default:b") ||
+		!strings.HasSuffix(lines[2], "init  default:a") {
+		t.Errorf("unexpected table:\n%s", buf.String())
+	}
+}
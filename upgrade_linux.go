@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// upgradeReadyEnv contains the file descriptor, which is used by the upgraded process to report that it is running
+const upgradeReadyEnv = "BOOT_UPGRADE_READY"
+
+// upgradeCommand returns the executable and the arguments of the upgraded process
+var upgradeCommand = func() (string, []string, error) {
+	path, err := os.Executable()
+	return path, os.Args[1:], err
+}
+
+// upgrade is an optional component, which replaces the process with a new one without dropping connections.
+type upgrade struct {
+	// Timeout is the time in milliseconds the new process has to reach the running phase.
+	Timeout  int      `boot:"config,key:BOOT_UPGRADE_TIMEOUT,default:60000"`
+	Eventbus EventBus `boot:"wire"`
+	session  *Session
+	signals  chan os.Signal
+	done     chan struct{}
+	stopOnce sync.Once
+	mutex    sync.Mutex
+	child    *os.Process
+}
+
+var _ Process = (*upgrade)(nil) // Verify conformity to Process
+
+var (
+	// errUpgradeFailed is returned when the new process didn't reach the running phase
+	errUpgradeFailed = errors.New("upgraded process didn't start")
+	// errUpgradeStopped is the cause of errUpgradeFailed, if the component was stopped during the upgrade
+	errUpgradeStopped = errors.New("upgrade stopped")
+)
+
+func newUpgrade(s *Session) *upgrade {
+	return &upgrade{
+		session: s,
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// RegisterUpgrade registers the optional upgrade component. On SIGUSR2 the executable is started again and
+// all listeners of the Listeners component are passed to the new process. As soon as the new process is
+// running, this session will be stopped gracefully.
+func (s *Session) RegisterUpgrade() error {
+	return s.register(DefaultName, func() Component {
+		return newUpgrade(s)
+	}, false)
+}
+
+// Init is described in the Component interface
+func (u *upgrade) Init() error {
+	return u.Eventbus.Subscribe(func(event RunningEvent) {
+		u.notifyParent()
+	})
+}
+
+// Start is described in the Process interface
+func (u *upgrade) Start() error {
+	signal.Notify(u.signals, syscall.SIGUSR2)
+	defer signal.Stop(u.signals)
+	for {
+		select {
+		case <-u.done:
+			return nil
+		case sig := <-u.signals:
+			Logger.Warn.Printf("caught signal %s - upgrade initiated...", sig)
+			if err := u.upgrade(); err != nil {
+				Logger.Error.Printf("upgrade failed: %v", err)
+			}
+		}
+	}
+}
+
+// Stop is described in the Process interface
+func (u *upgrade) Stop() error {
+	u.stopOnce.Do(func() {
+		close(u.done)
+	})
+	return nil
+}
+
+// upgrade starts the new process and shuts the session down, as soon as the new process is running.
+func (u *upgrade) upgrade() error {
+	names, fds := duplicateListeners(u.session.listeners)
+	defer func() {
+		for _, fd := range fds {
+			_ = syscall.Close(int(fd))
+		}
+	}()
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer ready.Close()
+	path, args, err := upgradeCommand()
+	if err != nil {
+		_ = readyWriter.Close()
+		return err
+	}
+	env := append(os.Environ(),
+		upgradeListenersEnv+"="+strings.Join(names, ":"),
+		upgradeReadyEnv+"="+strconv.Itoa(upgradeFdsStart+len(fds)))
+	// syscall.ForkExec is used, because os/exec changes the blocking mode of the listeners
+	files := append([]uintptr{os.Stdin.Fd(), os.Stdout.Fd(), os.Stderr.Fd()}, fds...)
+	pid, err := syscall.ForkExec(path, append([]string{path}, args...), &syscall.ProcAttr{
+		Env:   env,
+		Files: append(files, readyWriter.Fd()),
+	})
+	_ = readyWriter.Close()
+	if err != nil {
+		return err
+	}
+	child, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	u.mutex.Lock()
+	u.child = child
+	u.mutex.Unlock()
+	Logger.Info.Printf("started upgraded process %d with %d listeners", pid, len(fds))
+	// the pipe is closed without a message, if the new process exits
+	_ = ready.SetReadDeadline(time.Now().Add(time.Duration(u.Timeout) * time.Millisecond))
+	read := make(chan error, 1)
+	go func() {
+		_, err := ready.Read(make([]byte, 1))
+		read <- err
+	}()
+	select {
+	case err = <-read:
+	case <-u.done:
+		err = errUpgradeStopped
+	}
+	if err != nil {
+		_ = child.Kill()
+		go func() {
+			_, _ = child.Wait()
+		}()
+		return fmt.Errorf("%w: %v", errUpgradeFailed, err)
+	}
+	Logger.Info.Printf("upgraded process %d is running - shutting down", pid)
+	return u.session.Shutdown()
+}
+
+// duplicateListeners returns duplicates of the listener file descriptors with their names. Listeners, which
+// can't be passed to another process, are skipped.
+func duplicateListeners(registry *listenerRegistry) ([]string, []uintptr) {
+	var names []string
+	var fds []uintptr
+	allNames, listeners := registry.all()
+	for i, listener := range listeners {
+		conn, ok := listener.(syscall.Conn)
+		if !ok {
+			Logger.Warn.Printf("listener %s can't be passed to another process", allNames[i])
+			continue
+		}
+		rawConn, err := conn.SyscallConn()
+		if err != nil {
+			Logger.Warn.Printf("listener %s can't be passed to another process: %v", allNames[i], err)
+			continue
+		}
+		var dup int
+		dupErr := rawConn.Control(func(fd uintptr) {
+			syscall.ForkLock.RLock()
+			defer syscall.ForkLock.RUnlock()
+			dup, err = syscall.Dup(int(fd))
+			if err == nil {
+				syscall.CloseOnExec(dup)
+			}
+		})
+		if dupErr != nil || err != nil {
+			Logger.Warn.Printf("listener %s can't be passed to another process: %v %v", allNames[i], dupErr, err)
+			continue
+		}
+		names = append(names, allNames[i])
+		fds = append(fds, uintptr(dup))
+	}
+	return names, fds
+}
+
+// notifyParent reports the previous process, that the upgraded process is running.
+func (u *upgrade) notifyParent() {
+	value, ok := os.LookupEnv(upgradeReadyEnv)
+	if !ok {
+		return
+	}
+	_ = os.Unsetenv(upgradeReadyEnv)
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		Logger.Error.Printf("invalid %s %s", upgradeReadyEnv, value)
+		return
+	}
+	file := os.NewFile(uintptr(fd), "upgrade")
+	defer file.Close()
+	if _, err := file.Write([]byte{1}); err != nil {
+		Logger.Error.Printf("notifying the previous process failed: %v", err)
+	}
+}
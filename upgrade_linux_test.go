@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type upgradeServerTest struct {
+	Listeners Listeners `boot:"wire"`
+	listener  net.Listener
+	reply     string
+	served    chan struct{}
+}
+
+func (c *upgradeServerTest) Init() error {
+	var err error
+	c.listener, err = c.Listeners.Listen("http", "tcp", "localhost:0")
+	return err
+}
+
+func (c *upgradeServerTest) Start() error {
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			return nil
+		}
+		_, _ = conn.Write([]byte(c.reply + "\n"))
+		_ = conn.Close()
+		close(c.served)
+	}
+}
+
+func (c *upgradeServerTest) Stop() error {
+	return c.listener.Close()
+}
+
+// TestUpgradeChild is executed as the upgraded process by TestUpgrade.
+func TestUpgradeChild(t *testing.T) {
+	if os.Getenv("BOOT_UPGRADE_TEST_CHILD") != "1" {
+		t.Skip("only executed by TestUpgrade")
+	}
+	server := &upgradeServerTest{reply: "child", served: make(chan struct{})}
+	s := newTestSessionWithOptions(Options{Signals: DefaultSignals()}, newUpgrade(nil), server)
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	select {
+	case <-server.served:
+	case <-time.After(10 * time.Second):
+		t.Error("no request served")
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	defer func(command func() (string, []string, error)) {
+		upgradeCommand = command
+	}(upgradeCommand)
+	upgradeCommand = func() (string, []string, error) {
+		return os.Args[0], []string{"-test.run=^TestUpgradeChild$"}, nil
+	}
+	t.Setenv("BOOT_UPGRADE_TEST_CHILD", "1")
+	u := newUpgrade(nil)
+	server := &upgradeServerTest{reply: "parent", served: make(chan struct{})}
+	s := newTestSessionWithOptions(Options{Signals: DefaultSignals()}, u, server)
+	u.session = s.Session
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	address := server.listener.Addr().String()
+	if err := u.upgrade(); err != nil {
+		t.Fatalf("upgrade() error = %v", err)
+	}
+	select {
+	case <-h.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("session not stopped after upgrade")
+	}
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	reply, _ := bufio.NewReader(conn).ReadString('\n')
+	if reply != "child\n" {
+		t.Errorf("reply = %q, want child", reply)
+	}
+	if state, err := u.child.Wait(); err != nil || !state.Success() {
+		t.Errorf("upgraded process failed: %v %v", state, err)
+	}
+}
+
+func TestUpgradeFailed(t *testing.T) {
+	defer func(command func() (string, []string, error)) {
+		upgradeCommand = command
+	}(upgradeCommand)
+	upgradeCommand = func() (string, []string, error) {
+		return "/bin/false", nil, nil
+	}
+	u := newUpgrade(nil)
+	s := newTestSessionWithOptions(Options{Signals: DefaultSignals()}, u, &upgradeServerTest{reply: "parent", served: make(chan struct{})})
+	u.session = s.Session
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := u.upgrade(); !errors.Is(err, errUpgradeFailed) {
+		t.Errorf("upgrade() error = %v, want %v", err, errUpgradeFailed)
+	}
+	select {
+	case <-h.Done():
+		t.Error("session must keep running after a failed upgrade")
+	default:
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestUpgradeStopped(t *testing.T) {
+	defer func(command func() (string, []string, error)) {
+		upgradeCommand = command
+	}(upgradeCommand)
+	upgradeCommand = func() (string, []string, error) {
+		return "/bin/sleep", []string{"10"}, nil
+	}
+	u := newUpgrade(nil)
+	s := newTestSessionWithOptions(Options{Signals: DefaultSignals()}, u)
+	u.session = s.Session
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	var child *os.Process
+	for i := 0; i < 50 && child == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		u.mutex.Lock()
+		child = u.child
+		u.mutex.Unlock()
+	}
+	if child == nil {
+		t.Fatal("upgraded process not started")
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if err := syscall.Kill(child.Pid, 0); err != nil {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Error("upgraded process not killed after Stop()")
+}
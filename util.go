@@ -24,6 +24,7 @@
 package boot
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -112,6 +113,32 @@ func (l logger) Unmute(logger *log.Logger) {
 	logger.SetOutput(os.Stdout)
 }
 
+// SetLevel unmutes the logger of the given level and all loggers with a higher level, while all lower
+// levels are muted. The supported levels are debug, info, warn, error and off.
+func (l logger) SetLevel(level string) error {
+	loggers := []*log.Logger{l.Debug, l.Info, l.Warn, l.Error}
+	index := -1
+	for i, name := range logLevels {
+		if strings.EqualFold(level, name) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("unknown log level %s", level)
+	}
+	for i, lg := range loggers {
+		if i < index {
+			l.Mute(lg)
+		} else {
+			l.Unmute(lg)
+		}
+	}
+	return nil
+}
+
+// logLevels contains the names of the log levels in ascending order
+var logLevels = []string{"debug", "info", "warn", "error", "off"}
+
 var (
 	// Logger contains a debug, info, warning and error logger, which is used for fine-grained log
 	// output. Every logger can be muted or unmuted separately.
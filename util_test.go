@@ -24,6 +24,8 @@
 package boot
 
 import (
+	"io"
+	"log"
 	"reflect"
 	"testing"
 )
@@ -204,3 +206,34 @@ func TestLoggerUnmute(t *testing.T) {
 		Logger.Unmute(Logger.Debug)
 	})
 }
+
+func TestLoggerSetLevel(t *testing.T) {
+	defer func() {
+		_ = Logger.SetLevel("info")
+	}()
+	tests := []struct {
+		level   string
+		muted   []bool
+		wantErr bool
+	}{
+		{level: "debug", muted: []bool{false, false, false, false}},
+		{level: "INFO", muted: []bool{true, false, false, false}},
+		{level: "warn", muted: []bool{true, true, false, false}},
+		{level: "error", muted: []bool{true, true, true, false}},
+		{level: "off", muted: []bool{true, true, true, true}},
+		{level: "trace", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			err := Logger.SetLevel(tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetLevel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for i, lg := range []*log.Logger{Logger.Debug, Logger.Info, Logger.Warn, Logger.Error} {
+				if muted := lg.Writer() == io.Discard; tt.muted != nil && muted != tt.muted[i] {
+					t.Errorf("SetLevel() logger %d muted = %v, want %v", i, muted, tt.muted[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validator is an optional interface of a component, which validates the injected dependencies and
+// configuration values. Validate is called before any component is initialized.
+type Validator interface {
+	// Validate returns an error, if the component is misconfigured.
+	Validate() error
+}
+
+// ValidationError contains the errors of all components, which couldn't be injected or failed to validate,
+// so all problems of a misconfigured deployment are reported at once.
+type ValidationError struct {
+	// Errors contains the injection or validation error by the full name of the component
+	Errors map[string]error
+}
+
+func newValidationError() *ValidationError {
+	return &ValidationError{Errors: make(map[string]error)}
+}
+
+var _ error = (*ValidationError)(nil) // force error to confirm to error interface
+
+// Error is used to confirm to the error interface
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = name + ": " + e.Errors[name].Error()
+	}
+	return fmt.Sprintf("validation failed for %d component(s): %s", len(names), strings.Join(msgs, "; "))
+}
+
+// Is reports whether the target matches any of the errors. Every error, which isn't caused by the wiring, matches
+// ErrConfiguration.
+func (e *ValidationError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+		if target == ErrConfiguration && !errors.Is(err, ErrInjection) { //nolint:errorlint // sentinel comparison required
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first validation error, which matches the target.
+func (e *ValidationError) As(target any) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// validationFailure marks errors of a component, which failed to validate.
+type validationFailure struct {
+	error
+}
+
+// Unwrap returns the underlying error.
+func (e *validationFailure) Unwrap() error {
+	return e.error
+}
+
+// validateComponents validates all components implementing the Validator and adds the errors to the validation
+// error, which is returned if it contains any error. The components, which failed to validate, are marked as
+// failed.
+func validateComponents(entries []*componentManager, validationErr *ValidationError) error {
+	for _, regEntry := range entries {
+		validator, ok := regEntry.component.(Validator)
+		if !ok {
+			continue
+		}
+		Logger.Debug.Printf("validating %s\n", regEntry.getFullName())
+		if err := validate(validator); err != nil {
+			Logger.Error.Printf("validating %s failed: %v", regEntry.getFullName(), err)
+			validationErr.Errors[regEntry.getFullName()] = err
+			regEntry.setState(Failed, &validationFailure{err})
+		}
+	}
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// validate calls the validator and converts a panic into an error.
+func validate(validator Validator) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("validation panicked: %v", r)
+		}
+	}()
+	return validator.Validate()
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2021-2023 boot-go
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package boot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errValidationTest = errors.New("port out of range")
+
+type validatorTest struct {
+	Port     int      `boot:"config,key:VALIDATOR_TEST_PORT,default:0"`
+	Eventbus EventBus `boot:"wire"`
+	panics   bool
+	validate int
+	init     int
+}
+
+func (c *validatorTest) Validate() error {
+	c.validate++
+	if c.panics {
+		panic("invalid")
+	}
+	if c.Eventbus == nil {
+		return errors.New("eventbus not wired")
+	}
+	if c.Port <= 0 {
+		return errValidationTest
+	}
+	return nil
+}
+
+func (c *validatorTest) Init() error {
+	c.init++
+	return nil
+}
+
+type validatorOtherTest struct {
+	Port int `boot:"config,key:VALIDATOR_TEST_PORT,default:0"`
+	init int
+}
+
+func (c *validatorOtherTest) Validate() error {
+	if c.Port <= 0 {
+		return errValidationTest
+	}
+	return nil
+}
+
+func (c *validatorOtherTest) Init() error {
+	c.init++
+	return nil
+}
+
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		port       string
+		panics     bool
+		wantErrors int
+	}{
+		{name: "valid", port: "8080"},
+		{name: "invalid", port: "0", wantErrors: 2},
+		{name: "panic", port: "8080", panics: true, wantErrors: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VALIDATOR_TEST_PORT", tt.port)
+			first, second := &validatorTest{panics: tt.panics}, &validatorOtherTest{}
+			s := newTestSession(first, second, &bootProcessesComponent{})
+			h, err := s.Start(context.Background())
+			if tt.wantErrors == 0 {
+				if err != nil {
+					t.Fatalf("Start() error = %v", err)
+				}
+				_ = h.Stop(context.Background())
+				if first.validate != 1 || first.init != 1 {
+					t.Errorf("Validate() called %d times and Init() called %d times, want 1", first.validate, first.init)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Errors) != tt.wantErrors {
+				t.Fatalf("Start() error = %v, want %d validation errors", err, tt.wantErrors)
+			}
+			if !errors.Is(err, ErrConfiguration) || ExitCode(err) != ExitCodeConfiguration {
+				t.Errorf("Start() error = %v, want %v", err, ErrConfiguration)
+			}
+			if first.init != 0 || second.init != 0 {
+				t.Errorf("Init() must not be called after a failed validation")
+			}
+			failed := 0
+			for _, info := range s.Components() {
+				if strings.HasPrefix(info.Type, "github.com/boot-go/boot/validator") && info.State == Failed && info.Err != nil {
+					failed++
+				}
+			}
+			if failed != tt.wantErrors {
+				t.Errorf("%d components failed, want %d", failed, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	err := &ValidationError{Errors: map[string]error{"default:b": errValidationTest, "default:a": errors.New("missing")}}
+	want := "validation failed for 2 component(s): default:a: missing; default:b: port out of range"
+	if err.Error() != want {
+		t.Errorf("Error() = %s, want %s", err.Error(), want)
+	}
+	if !errors.Is(err, errValidationTest) || errors.Is(err, ErrInjection) {
+		t.Errorf("Is() doesn't match the validation errors")
+	}
+}
+
+func TestValidationAddComponent(t *testing.T) {
+	t.Setenv("VALIDATOR_TEST_PORT", "0")
+	s := newTestSession(&bootProcessesComponent{})
+	h, err := s.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = h.Stop(context.Background()) }()
+	cmp := &validatorTest{}
+	if err := s.AddComponent(DefaultName, func() Component { return cmp }); !errors.Is(err, errValidationTest) {
+		t.Errorf("AddComponent() error = %v, want %v", err, errValidationTest)
+	}
+	for _, info := range s.Components() {
+		if strings.HasSuffix(info.Type, "validatorTest") {
+			t.Errorf("invalid component must not be registered")
+		}
+	}
+	if err := h.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v, the rejected component must not fail the session", err)
+	}
+}
+
+type validatorWiringTest struct {
+	Config *exitCodeConfigTest `boot:"wire"`
+}
+
+func (c *validatorWiringTest) Init() error { return nil }
+
+func TestValidationInjectionErrors(t *testing.T) {
+	t.Setenv("VALIDATOR_TEST_PORT", "0")
+	s := newTestSession(&validatorTest{}, &validatorWiringTest{}, &exitCodeConfigTest{}, &bootMissingDependencyComponent{})
+	_, err := s.Start(context.Background())
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Start() error = %v, want ValidationError", err)
+	}
+	want := map[string]error{
+		"default:github.com/boot-go/boot/validatorTest":                  errValidationTest,
+		"default:github.com/boot-go/boot/exitCodeConfigTest":             ErrConfiguration,
+		"default:github.com/boot-go/boot/bootMissingDependencyComponent": ErrInjection,
+	}
+	if len(validationErr.Errors) != len(want) {
+		t.Errorf("Start() error = %v, want %d errors", err, len(want))
+	}
+	for name, target := range want {
+		if !errors.Is(validationErr.Errors[name], target) {
+			t.Errorf("error of %s = %v, want %v", name, validationErr.Errors[name], target)
+		}
+	}
+	if !errors.Is(err, ErrInjection) || ExitCode(err) != ExitCodeConfiguration {
+		t.Errorf("Start() error = %v, want %v and exit code %d", err, ErrInjection, ExitCodeConfiguration)
+	}
+}